@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/icarus-go/netspeed/pkg/command"
 	"github.com/icarus-go/netspeed/pkg/commands"
+	"github.com/icarus-go/netspeed/pkg/config"
 	"github.com/icarus-go/netspeed/pkg/proxy"
+	"github.com/icarus-go/netspeed/pkg/tester"
 )
 
 func main() {
@@ -20,9 +28,27 @@ func main() {
 
 	// 定义全局 flags
 	var (
-		proxyURL   = flag.String("proxy", "", "设置代理 (支持 http://, socks5://, https://)")
-		configFile = flag.String("config", "", "自定义测试站点配置文件（JSON 格式）")
-		timeout    = flag.Int("timeout", 10, "请求超时时间（秒）")
+		proxyURL    = flag.String("proxy", "", "设置代理 (支持 http://, socks5://, https://)")
+		configFile  = flag.String("config", "", "配置文件路径或 http(s) URL（JSON/YAML/TOML），可包含全局选项、测试站点和 IP API 提供商")
+		profile     = flag.String("profile", "", "使用配置文件中的命名配置方案（可继承，覆盖站点、代理、超时、阈值等）")
+		configSHA   = flag.String("config-sha256", "", "配置文件内容的 SHA-256，不一致时拒绝加载（用于校验远程配置）")
+		timeout     = flag.Int("timeout", 10, "请求超时时间（秒）")
+		detail      = flag.Bool("detail", false, "显示 DNS/连接/TLS/首字节等各阶段耗时")
+		samples     = flag.Int("samples", 1, "每个站点的采样次数，大于 1 时输出中位数/P95/抖动统计")
+		concurrency = flag.Int("concurrency", 0, "同时测试的最大站点数（0 表示不限）")
+		rateLimit   = flag.Float64("rps", 0, "每秒最多建立的探测连接数（0 表示不限，-family both、-conn both 时每个地址族、冷/热连接各计一次）")
+		perHost     = flag.Bool("per-host", false, "同一主机的站点串行测试，避免互相干扰")
+		connMode    = flag.String("conn", "pooled", "连接方式: pooled 复用连接, cold 每次新建连接, both 同时测量冷/热连接")
+		family      = flag.String("family", "", "IP 地址族: 4 仅 IPv4, 6 仅 IPv6, both 分别测试并对比")
+		certReport  = flag.Bool("cert", false, "显示 HTTPS/TLS 站点的证书报告")
+		certWarn    = flag.Int("cert-warn-days", 14, "证书剩余天数低于该值时标记为降级（0 表示不检查）")
+		retries     = flag.Int("retries", 0, "探测失败后最多重试的次数（指数退避并加入随机抖动）")
+		retryDelay  = flag.Int("retry-delay", 200, "第一次重试前的等待时间（毫秒），之后每次翻倍")
+		tags        = flag.String("tags", "", "只测试带有任一标签（或分组）的站点，多个用逗号分隔")
+		excludeTags = flag.String("exclude-tags", "", "排除带有任一标签（或分组）的站点，多个用逗号分隔")
+		only        = flag.String("only", "", "只测试指定名称的站点，多个用逗号分隔")
+		groupBy     = flag.String("group-by", "", "按分组统计可用率和平均延迟: group 按 Group, tag 按 Tags")
+		redirect    = flag.String("redirect", "follow", "重定向策略: follow 跟随, none 不跟随, 数字表示最多跟随的次数")
 	)
 
 	// 让每个命令定义自己的 flags
@@ -33,8 +59,109 @@ func main() {
 	// 解析 flags
 	flag.Parse()
 
-	// 初始化 HTTP 客户端
-	httpClient, err := proxy.InitHTTPClient(*proxyURL, time.Duration(*timeout)*time.Second)
+	// 只看命令行参数：环境变量和配置文件中的选项不算用户在本次调用中指定了操作
+	showHelp := flag.NFlag() == 0
+
+	// 收到 SIGINT/SIGTERM 时取消上下文，中断远程配置的获取和进行中的探测
+	runCtx, cancel := notifyContext()
+	defer cancel(nil)
+
+	// 加载配置文件所需的引导参数只取命令行和环境变量（config、config-sha256、profile 不能写在配置文件中）
+	*configFile = config.Lookup(flag.CommandLine, "config")
+	bootstrapProfile := config.Lookup(flag.CommandLine, "profile")
+	loaderOpts := []config.Option{
+		config.WithContext(runCtx),
+		config.WithFlags(flag.CommandLine),
+		config.WithSHA256(config.Lookup(flag.CommandLine, "config-sha256")),
+		config.WithProfile(bootstrapProfile),
+	}
+
+	// 远程配置经由命令行或环境变量中设置的代理获取
+	var httpClient *http.Client
+	var err error
+	bootstrapProxy := config.Lookup(flag.CommandLine, "proxy")
+	if config.IsRemote(*configFile) {
+		var bootstrapTimeout int
+		if bootstrapTimeout, err = strconv.Atoi(config.Lookup(flag.CommandLine, "timeout")); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 配置错误: 环境变量 %s 无效: %v\n", config.EnvName("timeout"), err)
+			os.Exit(1)
+		}
+		if httpClient, err = proxy.InitHTTPClient(bootstrapProxy, time.Duration(bootstrapTimeout)*time.Second); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 代理配置错误: %v\n", err)
+			os.Exit(1)
+		}
+		loaderOpts = append(loaderOpts, config.WithHTTPClient(httpClient))
+	}
+
+	// 配置方案中的代理、超时等选项需要在初始化 HTTP 客户端之前合并
+	if bootstrapProfile != "" && *configFile == "" {
+		fmt.Fprintf(os.Stderr, "❌ 参数错误: -profile 需要配置文件（-config 或 %s）\n", config.EnvName("config"))
+		os.Exit(1)
+	}
+	// 配置文件只读取一次，各命令复用同一份配置，避免重复获取远程配置
+	appConfig := &config.Config{}
+	var rawConfig *config.Config
+	if *configFile != "" {
+		loader := config.NewLoader(loaderOpts...)
+		rawConfig, err = loader.Validate(*configFile)
+		if err == nil {
+			appConfig, err = loader.Select(rawConfig)
+		}
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "⚠️  已中断")
+			os.Exit(exitCode(runCtx))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 加载配置失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	// 未在命令行中指定的选项一次性按 环境变量 > 配置文件 的优先级赋值
+	if err := config.ApplyOptions(flag.CommandLine, appConfig.Options); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 配置错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 初始化 HTTP 客户端（配置文件中设置了其他代理时重新创建）
+	if httpClient == nil || *proxyURL != bootstrapProxy {
+		if httpClient, err = proxy.InitHTTPClient(*proxyURL, time.Duration(*timeout)*time.Second); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 代理配置错误: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	httpClient.Timeout = time.Duration(*timeout) * time.Second
+	// 获取远程配置时可能先创建过一次客户端，代理信息在确定最终使用的代理后只输出一次
+	proxy.PrintInfo(*proxyURL)
+
+	mode, err := tester.ParseConnMode(*connMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 参数错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	ipFamily, err := tester.ParseFamily(*family)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 参数错误: %v\n", err)
+		os.Exit(1)
+	}
+	// 经由代理时目标站点的地址由代理解析，限定地址族得到的对比没有意义
+	if ipFamily != tester.FamilyAny && proxy.Configured(*proxyURL) {
+		fmt.Fprintln(os.Stderr, "❌ 参数错误: -family 不能与代理（-proxy 或 HTTP_PROXY/HTTPS_PROXY/ALL_PROXY）同时使用，经由代理时目标站点的地址族由代理决定")
+		os.Exit(1)
+	}
+
+	if *groupBy != "" && *groupBy != tester.GroupByGroup && *groupBy != tester.GroupByTag {
+		fmt.Fprintf(os.Stderr, "❌ 参数错误: 无效的分组方式: %s (支持 group, tag)\n", *groupBy)
+		os.Exit(1)
+	}
+
+	if _, err := tester.ParseRedirectPolicy(*redirect); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 参数错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 非 HTTP 探测（tcp/tls/dns）经由 SOCKS5 代理拨号
+	dialContext, err := proxy.InitDialContext(*proxyURL)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ 代理配置错误: %v\n", err)
 		os.Exit(1)
@@ -42,15 +169,37 @@ func main() {
 
 	// 创建命令执行上下文
 	ctx := &command.Context{
-		HTTPClient: httpClient,
-		Flags:      flag.CommandLine,
-		ProxyURL:   *proxyURL,
-		Timeout:    *timeout,
-		ConfigFile: *configFile,
+		Ctx:          runCtx,
+		HTTPClient:   httpClient,
+		DialContext:  dialContext,
+		Flags:        flag.CommandLine,
+		ProxyURL:     *proxyURL,
+		Timeout:      *timeout,
+		ConfigFile:   *configFile,
+		ConfigSHA256: *configSHA,
+		Profile:      *profile,
+		Detail:       *detail,
+		Samples:      *samples,
+		Concurrency:  *concurrency,
+		RateLimit:    *rateLimit,
+		PerHost:      *perHost,
+		ConnMode:     string(mode),
+		Family:       string(ipFamily),
+		CertReport:   *certReport,
+		CertWarnDays: *certWarn,
+		Redirect:     *redirect,
+		Retries:      *retries,
+		RetryDelay:   *retryDelay,
+		Tags:         *tags,
+		ExcludeTags:  *excludeTags,
+		Only:         *only,
+		GroupBy:      *groupBy,
+		Providers:    appConfig.Providers,
+		Config:       rawConfig,
 	}
 
-	// 如果没有任何 flag 被设置，显示帮助
-	if flag.NFlag() == 0 {
+	// 如果命令行中没有指定任何 flag，显示帮助
+	if showHelp {
 		helpCmd := commands.NewHelpCommand()
 		helpFlags := flag.NewFlagSet("help", flag.ExitOnError)
 		helpCmd.DefineFlags(helpFlags)
@@ -62,6 +211,13 @@ func main() {
 	// 执行所有激活的命令
 	for _, cmd := range registry.All() {
 		if err := cmd.Execute(ctx); err != nil {
+			if errors.Is(err, command.ErrStop) {
+				return
+			}
+			if errors.Is(err, context.Canceled) {
+				fmt.Fprintln(os.Stderr, "⚠️  已中断")
+				os.Exit(exitCode(runCtx))
+			}
 			fmt.Fprintf(os.Stderr, "❌ 执行命令失败: %v\n", err)
 			os.Exit(1)
 		}
@@ -72,11 +228,16 @@ func main() {
 func registerCommands(registry *command.Registry) {
 	// 按优先级注册命令
 	cmds := []command.Command{
-		commands.NewHelpCommand(),     // 优先级 1
-		commands.NewIPCommand(),       // 优先级 10
-		commands.NewIPScoreCommand(),  // 优先级 15
-		commands.NewTestCommand(),     // 优先级 20
-		commands.NewWatchCommand(),    // 优先级 30
+		commands.NewHelpCommand(),           // 优先级 1
+		commands.NewValidateConfigCommand(), // 优先级 2
+		commands.NewListProfilesCommand(),   // 优先级 3
+		commands.NewIPCommand(),             // 优先级 10
+		commands.NewIPScoreCommand(),        // 优先级 15
+		commands.NewTestCommand(),           // 优先级 20
+		commands.NewPageLoadCommand(),       // 优先级 22
+		commands.NewBandwidthCommand(),      // 优先级 25
+		commands.NewBufferbloatCommand(),    // 优先级 27
+		commands.NewWatchCommand(),          // 优先级 30
 	}
 
 	for _, cmd := range cmds {
@@ -86,3 +247,44 @@ func registerCommands(registry *command.Registry) {
 		}
 	}
 }
+
+// signalError 记录导致退出的信号
+type signalError struct {
+	sig os.Signal
+}
+
+func (e signalError) Error() string {
+	return "收到信号: " + e.sig.String()
+}
+
+// notifyContext 创建在收到 SIGINT/SIGTERM 时取消的上下文
+func notifyContext() (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			cancel(signalError{sig: sig})
+			// 再次收到信号时立即退出
+			<-sigCh
+			os.Exit(exitCode(ctx))
+		case <-ctx.Done():
+		}
+		signal.Stop(sigCh)
+	}()
+
+	return ctx, cancel
+}
+
+// exitCode 按惯例返回 128 + 信号编号（SIGINT 为 130，SIGTERM 为 143）
+func exitCode(ctx context.Context) int {
+	var sigErr signalError
+	if errors.As(context.Cause(ctx), &sigErr) {
+		if sig, ok := sigErr.sig.(syscall.Signal); ok {
+			return 128 + int(sig)
+		}
+	}
+	return 1
+}
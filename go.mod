@@ -2,4 +2,8 @@ module github.com/icarus-go/netspeed
 
 go 1.25.1
 
-require golang.org/x/net v0.47.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	golang.org/x/net v0.47.0
+	gopkg.in/yaml.v3 v3.0.1
+)
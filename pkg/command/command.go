@@ -1,10 +1,19 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"net"
 	"net/http"
+
+	"github.com/icarus-go/netspeed/pkg/config"
+	"github.com/icarus-go/netspeed/pkg/ipinfo"
 )
 
+// ErrStop 命令已完成全部工作，不再执行后续命令（不视为失败）
+var ErrStop = errors.New("停止执行后续命令")
+
 // Command 命令接口
 type Command interface {
 	// Name 返回命令名称
@@ -25,9 +34,15 @@ type Command interface {
 
 // Context 命令执行上下文
 type Context struct {
+	// Ctx 可取消的上下文（收到 SIGINT/SIGTERM 时取消）
+	Ctx context.Context
+
 	// HTTPClient HTTP 客户端（已配置代理）
 	HTTPClient *http.Client
 
+	// DialContext 非 HTTP 探测的拨号函数（配置 SOCKS5 代理时经由代理，nil 表示直连）
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+
 	// Flags flag 集合
 	Flags *flag.FlagSet
 
@@ -37,6 +52,75 @@ type Context struct {
 	// Timeout 超时时间（秒）
 	Timeout int
 
-	// ConfigFile 配置文件路径
+	// ConfigFile 配置文件路径或 http(s) URL
 	ConfigFile string
+
+	// ConfigSHA256 配置文件内容的 SHA-256（为空时不校验）
+	ConfigSHA256 string
+
+	// Profile 使用的配置方案（为空时不使用）
+	Profile string
+
+	// Detail 是否输出各阶段耗时明细
+	Detail bool
+
+	// Samples 每个站点的采样次数
+	Samples int
+
+	// Concurrency 同时测试的最大站点数（0 表示不限）
+	Concurrency int
+
+	// RateLimit 每秒最多发出的探测请求数（0 表示不限）
+	RateLimit float64
+
+	// PerHost 同一主机的站点是否串行测试
+	PerHost bool
+
+	// ConnMode HTTP 探测的连接复用方式（pooled / cold / both）
+	ConnMode string
+
+	// Family IP 地址族（空 / 4 / 6 / both）
+	Family string
+
+	// CertReport 是否输出证书报告
+	CertReport bool
+
+	// CertWarnDays 证书剩余天数低于该值时标记为降级（0 表示不检查）
+	CertWarnDays int
+
+	// Redirect 重定向策略（follow / none / 最大跳数）
+	Redirect string
+
+	// Retries 探测失败后最多重试的次数
+	Retries int
+
+	// RetryDelay 第一次重试前的等待时间（毫秒）
+	RetryDelay int
+
+	// Tags 只测试带有任一标签（或分组）的站点，逗号分隔
+	Tags string
+
+	// ExcludeTags 排除带有任一标签（或分组）的站点，逗号分隔
+	ExcludeTags string
+
+	// Only 只测试指定名称的站点，逗号分隔
+	Only string
+
+	// GroupBy 分组统计方式（空 / group / tag）
+	GroupBy string
+
+	// Providers 配置文件中的 IP API 提供商（为空时使用内置列表）
+	Providers []ipinfo.Provider
+
+	// Config 启动时读取并校验的配置（未合并配置方案、未筛选站点），未指定配置文件时为 nil
+	// 各命令通过 config.Loader.Select 复用，避免重复获取远程配置
+	Config *config.Config
+}
+
+// Context 返回可取消的上下文，未设置时返回 context.Background()
+func (c *Context) Context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
 }
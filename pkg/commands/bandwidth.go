@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/command"
+	"github.com/icarus-go/netspeed/pkg/config"
+	"github.com/icarus-go/netspeed/pkg/output"
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// BandwidthCommand 带宽（吞吐）测试命令
+type BandwidthCommand struct {
+	enabled  *bool
+	upload   *bool
+	duration *int
+	sizeMB   *int
+	streams  *int
+}
+
+// NewBandwidthCommand 创建带宽测试命令
+func NewBandwidthCommand() *BandwidthCommand {
+	return &BandwidthCommand{}
+}
+
+// Name 返回命令名称
+func (c *BandwidthCommand) Name() string {
+	return "bandwidth"
+}
+
+// Description 返回命令描述
+func (c *BandwidthCommand) Description() string {
+	return "测试下载/上传带宽"
+}
+
+// DefineFlags 定义命令的 flag 参数
+func (c *BandwidthCommand) DefineFlags(flags *flag.FlagSet) {
+	c.enabled = flags.Bool("bandwidth", false, "测试下载带宽")
+	c.upload = flags.Bool("upload", false, "测试上传带宽")
+	c.duration = flags.Int("bandwidth-duration", 10, "带宽测试时长（秒）")
+	c.sizeMB = flags.Int("bandwidth-size", 0, "带宽测试传输量上限（MB，0 表示不限）")
+	c.streams = flags.Int("streams", 4, "带宽测试并行连接数")
+}
+
+// Execute 执行命令
+func (c *BandwidthCommand) Execute(ctx *command.Context) error {
+	if !*c.enabled && !*c.upload {
+		return nil
+	}
+
+	cfg, err := loadConfig(ctx, config.NewLoader(loaderOptions(ctx)...))
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %v", err)
+	}
+	sites := cfg.Sites
+
+	var downloads, uploads []tester.Site
+	if *c.enabled {
+		downloads = bandwidthTargets(sites, func(s tester.Site) bool { return s.DownloadURL != "" })
+	}
+	if *c.upload {
+		uploads = bandwidthTargets(sites, func(s tester.Site) bool { return s.UploadURL != "" })
+	}
+
+	opts := tester.BandwidthOptions{
+		Duration: time.Duration(*c.duration) * time.Second,
+		Bytes:    int64(*c.sizeMB) << 20,
+		Streams:  *c.streams,
+	}
+
+	fmt.Printf("📶 开始带宽测试 %d 项 (每项 %d 秒, %d 个并行连接)...\n", len(downloads)+len(uploads), *c.duration, opts.Streams)
+	fmt.Println()
+
+	// 逐项测试，避免互相争抢带宽
+	timeout := time.Duration(ctx.Timeout) * time.Second
+	t := tester.NewTester(ctx.HTTPClient, timeout)
+	results := make([]tester.TestResult, 0, len(downloads)+len(uploads))
+	runCtx := ctx.Context()
+	for _, site := range downloads {
+		if runCtx.Err() != nil {
+			break
+		}
+		fmt.Printf("  ⏳ %s 下载...\n", site.Name)
+		results = append(results, t.TestDownload(runCtx, site, opts))
+	}
+	for _, site := range uploads {
+		if runCtx.Err() != nil {
+			break
+		}
+		fmt.Printf("  ⏳ %s 上传...\n", site.Name)
+		results = append(results, t.TestUpload(runCtx, site, opts))
+	}
+	fmt.Println()
+
+	output.PrintThroughputTable(results)
+	output.PrintSummary(results)
+
+	return runCtx.Err()
+}
+
+// Priority 返回命令优先级
+func (c *BandwidthCommand) Priority() int {
+	return 25
+}
+
+// bandwidthTargets 筛选出满足条件的站点，配置中没有时从内置列表中筛选
+func bandwidthTargets(sites []tester.Site, match func(tester.Site) bool) []tester.Site {
+	var targets []tester.Site
+	for _, site := range sites {
+		if match(site) {
+			targets = append(targets, site)
+		}
+	}
+	if len(targets) > 0 {
+		return targets
+	}
+
+	for _, site := range tester.DefaultBandwidthSites {
+		if match(site) {
+			targets = append(targets, site)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/command"
+	"github.com/icarus-go/netspeed/pkg/config"
+	"github.com/icarus-go/netspeed/pkg/output"
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// BufferbloatCommand 负载下延迟（缓冲膨胀）测试命令
+type BufferbloatCommand struct {
+	enabled  *bool
+	duration *int
+}
+
+// NewBufferbloatCommand 创建负载下延迟测试命令
+func NewBufferbloatCommand() *BufferbloatCommand {
+	return &BufferbloatCommand{}
+}
+
+// Name 返回命令名称
+func (c *BufferbloatCommand) Name() string {
+	return "bufferbloat"
+}
+
+// Description 返回命令描述
+func (c *BufferbloatCommand) Description() string {
+	return "测试下载/上传占满带宽时的延迟（缓冲膨胀）"
+}
+
+// DefineFlags 定义命令的 flag 参数
+func (c *BufferbloatCommand) DefineFlags(flags *flag.FlagSet) {
+	c.enabled = flags.Bool("bufferbloat", false, "测试下载/上传占满带宽时的延迟（缓冲膨胀）")
+	c.duration = flags.Int("bufferbloat-duration", 10, "负载下延迟测试每个负载阶段的时长（秒）")
+}
+
+// Execute 执行命令
+func (c *BufferbloatCommand) Execute(ctx *command.Context) error {
+	if !*c.enabled {
+		return nil
+	}
+
+	cfg, err := loadConfig(ctx, config.NewLoader(loaderOptions(ctx)...))
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %v", err)
+	}
+	sites := cfg.Sites
+
+	// 以第一个站点作为延迟测量目标，负载使用带宽测试的站点
+	target := sites[0]
+	var load tester.Site
+	if downloads := bandwidthTargets(sites, func(s tester.Site) bool { return s.DownloadURL != "" }); len(downloads) > 0 {
+		load.DownloadURL = downloads[0].DownloadURL
+	}
+	if uploads := bandwidthTargets(sites, func(s tester.Site) bool { return s.UploadURL != "" }); len(uploads) > 0 {
+		load.UploadURL = uploads[0].UploadURL
+	}
+
+	opts := tester.DefaultBufferbloatOptions
+	opts.Duration = time.Duration(*c.duration) * time.Second
+
+	fmt.Printf("🌊 开始负载下延迟测试: %s (空闲 %d 秒, 下载/上传各 %d 秒)...\n", target.Name, int(opts.Idle.Seconds()), *c.duration)
+	fmt.Println()
+
+	timeout := time.Duration(ctx.Timeout) * time.Second
+	t := tester.NewTester(ctx.HTTPClient, timeout, testerOptions(ctx)...)
+	runCtx := ctx.Context()
+	result := t.TestBufferbloat(runCtx, target, load, opts)
+
+	output.PrintBufferbloatTable([]tester.TestResult{result})
+	return runCtx.Err()
+}
+
+// Priority 返回命令优先级
+func (c *BufferbloatCommand) Priority() int {
+	return 27
+}
@@ -59,10 +59,40 @@ func (c *HelpCommand) showHelp() {
 	println("  -test             测试网站速度并以表格形式输出")
 	println("  -ip               获取当前 IP 地理信息")
 	println("  -purity           检测 IP 纯净度和风险评分")
+	println("  -bandwidth        测试下载带宽")
+	println("  -upload           测试上传带宽（可与 -bandwidth 同时使用）")
+	println("  -bandwidth-duration <秒>  带宽测试时长（默认 10 秒）")
+	println("  -bandwidth-size <MB>      带宽测试传输量上限（默认不限）")
+	println("  -streams <数量>   带宽测试并行连接数（默认 4）")
+	println("  -pageload         加载页面及其脚本、样式表和图片，测试完整页面加载耗时")
+	println("  -pageload-resources <数量>  页面加载测试最多加载的子资源数（默认 50）")
+	println("  -pageload-conns <数量>      页面加载测试每个主机的最大并行连接数（默认 6）")
+	println("  -bufferbloat      测试下载/上传占满带宽时的延迟（缓冲膨胀）")
+	println("  -bufferbloat-duration <秒>  每个负载阶段的时长（默认 10 秒）")
 	println("  -proxy <url>      设置代理 (支持 http://, socks5://, https://)")
-	println("  -watch <秒>       持续监控模式，指定刷新间隔（秒）")
-	println("  -config <文件>    自定义测试站点配置文件（JSON 格式）")
+	println("  -watch <秒>       持续监控模式，指定刷新间隔（秒），配置文件修改后自动重新加载")
+	println("  -config <文件|URL>  配置文件或 http(s) URL（JSON/YAML/TOML），可包含全局选项、站点和 IP API 提供商")
+	println("  -config-sha256 <哈希>  配置文件内容的 SHA-256，不一致时拒绝加载（远程配置防篡改）")
 	println("  -timeout <秒>     请求超时时间（默认 10 秒）")
+	println("  -detail           显示 DNS/连接/TLS/首字节等各阶段耗时")
+	println("  -samples <次数>   每个站点采样多次，输出中位数/P95/抖动统计（默认 1）")
+	println("  -concurrency <数> 同时测试的最大站点数（默认不限）")
+	println("  -rps <数>         每秒最多建立的探测连接数（默认不限，双栈、冷/热对比时分别计数）")
+	println("  -per-host         同一主机的站点串行测试")
+	println("  -conn <方式>      连接方式: pooled 复用连接（默认）, cold 每次新建, both 冷/热对比")
+	println("  -family <4|6|both> 限定 IP 地址族，both 时分别测试 IPv4/IPv6 并对比（不能与代理同时使用）")
+	println("  -cert             显示 HTTPS/TLS 站点的证书报告")
+	println("  -cert-warn-days <天>  证书剩余天数低于该值时标记为降级（默认 14）")
+	println("  -retries <次数>   探测失败后最多重试的次数，指数退避并加入随机抖动（默认 0）")
+	println("  -retry-delay <毫秒>  第一次重试前的等待时间，之后每次翻倍（默认 200）")
+	println("  -tags <标签,...>  只测试带有任一标签（或分组）的站点")
+	println("  -exclude-tags <标签,...>  排除带有任一标签（或分组）的站点")
+	println("  -only <名称,...>  只测试指定名称的站点")
+	println("  -group-by <方式>  按分组统计可用率和平均延迟: group 按 Group, tag 按 Tags")
+	println("  -redirect <策略>  重定向策略: follow 跟随（默认）, none 不跟随, 数字为最多跟随次数")
+	println("  -profile <名称>   使用配置文件中的命名配置方案（可继承，覆盖站点、代理、超时、阈值等）")
+	println("  -list-profiles    列出配置文件中的配置方案")
+	println("  -validate-config  只校验配置文件（语法、未知字段、URL、重复的名称和 URL），有问题时返回非零退出码")
 	println("  -help             显示此帮助信息")
 	println()
 	println("示例:")
@@ -73,13 +103,72 @@ func (c *HelpCommand) showHelp() {
 	println("  netspeed -test -proxy socks5://127.0.0.1:1080")
 	println("  netspeed -test -proxy http://proxy.example.com:8080")
 	println("  netspeed -test -config sites.example.json")
+	println("  netspeed -test -config netspeed.yaml")
+	println("  netspeed -validate-config -config netspeed.yaml")
+	println("  netspeed -test -config netspeed.yaml -profile office")
+	println("  netspeed -config netspeed.yaml -list-profiles")
+	println("  netspeed -test -config https://config.example.com/sites.json -config-sha256 <哈希>")
+	println("  NETSPEED_TIMEOUT=5 netspeed -test")
 	println("  netspeed -test -timeout 5")
+	println("  netspeed -test -detail")
+	println("  netspeed -test -samples 10")
+	println("  netspeed -test -watch 30 -conn cold")
+	println("  netspeed -test -family both")
+	println("  netspeed -ip -family both")
+	println("  netspeed -test -cert -cert-warn-days 30")
+	println("  netspeed -test -detail -redirect 3")
+	println("  netspeed -test -watch 30 -retries 2")
+	println("  netspeed -test -config sites.json -tags overseas -exclude-tags ai -group-by group")
+	println("  netspeed -test -config sites.json -only Google,GitHub")
+	println("  netspeed -test -config sites.json -concurrency 8 -rps 20 -per-host")
+	println("  netspeed -bandwidth -streams 8")
+	println("  netspeed -bandwidth -upload")
+	println("  netspeed -pageload -only GitHub")
+	println("  netspeed -bufferbloat")
 	println()
 	println("配置文件格式 (JSON):")
 	println(`  [
-    {"Name": "Google", "URL": "https://www.google.com"},
-    {"Name": "GitHub", "URL": "https://github.com"}
+    {"Name": "Google", "URL": "https://www.google.com", "Group": "overseas"},
+    {"Name": "GitHub", "URL": "https://github.com", "Tags": ["dev"]},
+    {"Name": "Mirror", "URL": "https://mirror.example.com",
+     "DownloadURL": "https://mirror.example.com/1GB.bin",
+     "UploadURL": "https://mirror.example.com/upload"},
+    {"Name": "Example", "URL": "https://www.example.com",
+     "ExpectStatus": ["2xx"], "BodyContains": "Example Domain",
+     "ExpectHeaders": {"Content-Type": "text/html"}},
+    {"Name": "API", "URL": "https://api.internal", "Method": "POST",
+     "Path": "/health", "Body": "{}", "Headers": {"Host": "api.internal"},
+     "Auth": {"Type": "bearer", "TokenEnv": "API_TOKEN"}},
+    {"Name": "Portal", "URL": "http://example.com",
+     "Redirect": "3", "RedirectHosts": ["example-cdn.net"]},
+    {"Name": "Origin", "URL": "https://origin.example.com",
+     "Timeout": 30, "Retries": 3},
+    {"Name": "Bastion", "URL": "tcp://bastion.example.com:22"},
+    {"Name": "DNS", "URL": "dns://8.8.8.8/www.google.com"}
   ]`)
+	println()
+	println("配置文件也可以是对象，在 Sites 之外设置全局和按分组/标签的评级阈值（毫秒）:")
+	println(`  {"Thresholds": {"Excellent": 300, "Good": 800, "Fair": 1500},
+   "TagThresholds": {"domestic": {"Excellent": 50, "Good": 100, "Fair": 300}},
+   "Sites": [{"Name": "Local", "URL": "https://intranet.example.com",
+              "Thresholds": {"Excellent": 20, "Good": 50, "Fair": 100}}]}`)
+	println()
+	println("Options 中可以设置任意全局选项（键为 flag 名），Providers 设置 IP API 提供商，")
+	println("扩展名为 .yaml/.yml/.toml 时按 YAML/TOML 解析:")
+	println(`  options:
+    proxy: socks5://127.0.0.1:1080
+    timeout: 5
+    watch: 30
+    tags: [overseas]
+  providers:
+    - {name: ipinfo.io, url: "https://ipinfo.io/json", format: json}
+  sites:
+    - {name: Google, url: "https://www.google.com"}
+  profiles:
+    office: {options: {proxy: "http://proxy.corp:3128", timeout: 5}}
+    home: {extends: office, options: {proxy: "socks5://127.0.0.1:1080"}}`)
+	println()
+	println("优先级: 命令行 > 环境变量（NETSPEED_ 加大写 flag 名，如 NETSPEED_PROXY、NETSPEED_CONFIG）> 配置文件 > 默认值")
 	println()
 	println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
@@ -9,6 +9,8 @@ import (
 
 	"github.com/icarus-go/netspeed/pkg/command"
 	"github.com/icarus-go/netspeed/pkg/ipinfo"
+	"github.com/icarus-go/netspeed/pkg/proxy"
+	"github.com/icarus-go/netspeed/pkg/tester"
 )
 
 // IPCommand IP 检测命令
@@ -70,16 +72,54 @@ func (c *IPCommand) Execute(ctx *command.Context) error {
 		httpClient = ctx.HTTPClient
 	}
 
-	detector := ipinfo.NewDetector(httpClient)
-	info, err := detector.Detect()
+	switch tester.Family(ctx.Family) {
+	case tester.Family4:
+		httpClient = proxy.ForceFamily(httpClient, "tcp4")
+	case tester.Family6:
+		httpClient = proxy.ForceFamily(httpClient, "tcp6")
+	case tester.FamilyBoth:
+		return c.detectDualStack(ctx, ipinfo.NewDetector(httpClient, ipinfo.WithProviders(ctx.Providers)))
+	}
+
+	detector := ipinfo.NewDetector(httpClient, ipinfo.WithProviders(ctx.Providers))
+	info, err := detector.Detect(ctx.Context())
 	if err != nil {
-		return fmt.Errorf("获取 IP 信息失败: %v", err)
+		return fmt.Errorf("获取 IP 信息失败: %w", err)
 	}
 
 	c.displayIPInfo(info, *c.origin)
 	return nil
 }
 
+// detectDualStack 分别检测 IPv4 和 IPv6 出口 IP
+func (c *IPCommand) detectDualStack(ctx *command.Context, detector *ipinfo.Detector) error {
+	info, err := detector.DetectDualStack(ctx.Context())
+	if err != nil {
+		return fmt.Errorf("获取 IP 信息失败: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println("🌐 IPv4 出口")
+	if info.IPv4Err != nil {
+		fmt.Printf("❌ IPv4 不可用: %v\n", info.IPv4Err)
+	} else {
+		c.displayIPInfo(info.IPv4, *c.origin)
+	}
+
+	fmt.Println()
+	fmt.Println("🌐 IPv6 出口")
+	if info.IPv6Err != nil {
+		fmt.Printf("❌ IPv6 不可用: %v\n", info.IPv6Err)
+	} else {
+		c.displayIPInfo(info.IPv6, *c.origin)
+	}
+
+	if info.IPv4Err != nil && info.IPv6Err != nil {
+		return fmt.Errorf("获取 IP 信息失败: IPv4 与 IPv6 均不可用")
+	}
+	return nil
+}
+
 // Priority 返回命令优先级
 func (c *IPCommand) Priority() int {
 	return 10
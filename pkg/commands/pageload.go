@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/command"
+	"github.com/icarus-go/netspeed/pkg/config"
+	"github.com/icarus-go/netspeed/pkg/output"
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// PageLoadCommand 页面加载测试命令
+type PageLoadCommand struct {
+	enabled   *bool
+	resources *int
+	conns     *int
+}
+
+// NewPageLoadCommand 创建页面加载测试命令
+func NewPageLoadCommand() *PageLoadCommand {
+	return &PageLoadCommand{}
+}
+
+// Name 返回命令名称
+func (c *PageLoadCommand) Name() string {
+	return "pageload"
+}
+
+// Description 返回命令描述
+func (c *PageLoadCommand) Description() string {
+	return "加载页面及其脚本、样式表和图片，测试完整页面加载耗时"
+}
+
+// DefineFlags 定义命令的 flag 参数
+func (c *PageLoadCommand) DefineFlags(flags *flag.FlagSet) {
+	c.enabled = flags.Bool("pageload", false, "加载页面及其脚本、样式表和图片，测试完整页面加载耗时")
+	c.resources = flags.Int("pageload-resources", tester.DefaultPageLoadOptions.MaxResources, "页面加载测试最多加载的子资源数")
+	c.conns = flags.Int("pageload-conns", tester.DefaultPageLoadOptions.PerHost, "页面加载测试每个主机的最大并行连接数")
+}
+
+// Execute 执行命令
+func (c *PageLoadCommand) Execute(ctx *command.Context) error {
+	if !*c.enabled {
+		return nil
+	}
+
+	cfg, err := loadConfig(ctx, config.NewLoader(loaderOptions(ctx)...))
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %v", err)
+	}
+	sites := cfg.Sites
+
+	opts := tester.PageLoadOptions{
+		MaxResources: *c.resources,
+		PerHost:      *c.conns,
+	}
+
+	fmt.Printf("🌐 开始页面加载测试 %d 个网站 (最多 %d 个子资源, 每个主机 %d 个连接)...\n", len(sites), opts.MaxResources, opts.PerHost)
+	fmt.Println()
+
+	// 逐个站点测试，避免页面之间争抢连接
+	timeout := time.Duration(ctx.Timeout) * time.Second
+	t := tester.NewTester(ctx.HTTPClient, timeout, testerOptions(ctx)...)
+	results := make([]tester.TestResult, 0, len(sites))
+	runCtx := ctx.Context()
+	for _, site := range sites {
+		if runCtx.Err() != nil {
+			break
+		}
+		fmt.Printf("  ⏳ %s...\n", site.Name)
+		results = append(results, t.TestPageLoad(runCtx, site, opts))
+	}
+	fmt.Println()
+
+	output.PrintPageLoadTable(results)
+	output.PrintSummary(results)
+
+	return runCtx.Err()
+}
+
+// Priority 返回命令优先级
+func (c *PageLoadCommand) Priority() int {
+	return 22
+}
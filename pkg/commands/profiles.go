@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/icarus-go/netspeed/pkg/command"
+	"github.com/icarus-go/netspeed/pkg/config"
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// ListProfilesCommand 列出配置方案命令
+type ListProfilesCommand struct {
+	enabled *bool
+}
+
+// NewListProfilesCommand 创建列出配置方案命令
+func NewListProfilesCommand() *ListProfilesCommand {
+	return &ListProfilesCommand{}
+}
+
+// Name 返回命令名称
+func (c *ListProfilesCommand) Name() string {
+	return "list-profiles"
+}
+
+// Description 返回命令描述
+func (c *ListProfilesCommand) Description() string {
+	return "列出配置文件中的配置方案"
+}
+
+// DefineFlags 定义命令的 flag 参数
+func (c *ListProfilesCommand) DefineFlags(flags *flag.FlagSet) {
+	c.enabled = flags.Bool("list-profiles", false, "列出配置文件中的配置方案，不执行测试")
+}
+
+// Execute 执行命令，列出后不再执行其他命令
+func (c *ListProfilesCommand) Execute(ctx *command.Context) error {
+	if !*c.enabled {
+		return nil
+	}
+	if ctx.ConfigFile == "" {
+		return fmt.Errorf("未指定配置文件（使用 -config 或环境变量 %s）", config.EnvName("config"))
+	}
+
+	// 不合并配置方案，展示文件中的原始设置（优先复用启动时已读取的配置）
+	cfg := ctx.Config
+	if cfg == nil {
+		var err error
+		cfg, err = config.NewLoader(
+			config.WithContext(ctx.Context()),
+			config.WithHTTPClient(ctx.HTTPClient),
+			config.WithSHA256(ctx.ConfigSHA256),
+		).Validate(ctx.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %v", err)
+		}
+	}
+
+	fmt.Printf("📋 配置方案 (%s)\n", ctx.ConfigFile)
+	fmt.Println()
+	if len(cfg.Profiles) == 0 {
+		fmt.Println("配置文件中没有配置方案")
+		return command.ErrStop
+	}
+
+	fmt.Printf("  %-15s %-15s %6s  %s\n", "名称", "继承", "站点数", "说明")
+	for _, name := range cfg.ProfileNames() {
+		p, err := cfg.ResolveProfile(name)
+		if err != nil {
+			return err
+		}
+
+		marker := " "
+		if name == ctx.Profile {
+			marker = "▶"
+		}
+		extends := p.Extends
+		if extends == "" {
+			extends = "-"
+		}
+		sites := len(cfg.Sites)
+		if p.Sites != nil {
+			sites = len(p.Sites)
+		}
+
+		fmt.Printf("%s %-15s %-15s %6d  %s\n", marker, name, extends, sites, p.Description)
+		if len(p.Options) > 0 {
+			fmt.Printf("    %s\n", config.FormatOptions(p.Options))
+		}
+		if p.Thresholds != nil {
+			th := tester.DefaultThresholds
+			if cfg.Thresholds != nil {
+				th = th.Merge(*cfg.Thresholds)
+			}
+			th = th.Merge(*p.Thresholds)
+			fmt.Printf("    评级阈值: %d/%d/%d ms\n", th.Excellent, th.Good, th.Fair)
+		}
+	}
+	fmt.Println()
+	fmt.Println("使用 -profile <名称> 选择配置方案，命令行参数优先于方案中的设置")
+	return command.ErrStop
+}
+
+// Priority 返回命令优先级
+func (c *ListProfilesCommand) Priority() int {
+	return 3
+}
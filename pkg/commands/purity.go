@@ -42,10 +42,10 @@ func (c *IPScoreCommand) Execute(ctx *command.Context) error {
 	fmt.Println("🔍 正在检测 IP 纯净度...")
 	fmt.Println()
 
-	detector := ipinfo.NewDetector(ctx.HTTPClient)
-	score, err := detector.DetectScore()
+	detector := ipinfo.NewDetector(ctx.HTTPClient, ipinfo.WithProviders(ctx.Providers))
+	score, err := detector.DetectScore(ctx.Context())
 	if err != nil {
-		return fmt.Errorf("检测 IP 纯净度失败: %v", err)
+		return fmt.Errorf("检测 IP 纯净度失败: %w", err)
 	}
 
 	c.displayIPScore(score)
@@ -3,6 +3,7 @@ package commands
 import (
 	"flag"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/icarus-go/netspeed/pkg/command"
@@ -43,28 +44,132 @@ func (c *TestCommand) Execute(ctx *command.Context) error {
 	}
 
 	// 加载站点配置
-	loader := config.NewLoader()
-	sites, err := loader.LoadSites(ctx.ConfigFile)
+	cfg, err := loadConfig(ctx, config.NewLoader(loaderOptions(ctx)...))
 	if err != nil {
 		return fmt.Errorf("加载配置失败: %v", err)
 	}
+	sites := cfg.Sites
 
 	fmt.Printf("🚀 开始测试 %d 个网站...\n", len(sites))
 	fmt.Println()
 
-	// 创建测试器并执行测试
+	// 创建测试器，逐行输出测试结果
 	timeout := time.Duration(ctx.Timeout) * time.Second
-	t := tester.NewTester(ctx.HTTPClient, timeout)
-	results := t.TestAll(sites)
+	t := tester.NewTester(ctx.HTTPClient, timeout, append(testerOptions(ctx), cfg.TesterOptions()...)...)
+	runTests(ctx, t, sites)
 
-	// 显示结果
-	output.PrintResultsTable(results)
-	output.PrintSummary(results)
-
-	return nil
+	// 被中断时已输出部分结果，返回取消原因以便以正确的退出码退出
+	return ctx.Context().Err()
 }
 
 // Priority 返回命令优先级
 func (c *TestCommand) Priority() int {
 	return 20
 }
+
+// loaderOptions 根据全局参数生成配置加载选项：选项名校验、远程配置的客户端、SHA-256 校验、配置方案和站点筛选条件（各测试命令共用）
+// 与启动时的校验一致，持续监控重新加载时同样拒绝未知的全局选项
+func loaderOptions(ctx *command.Context) []config.Option {
+	opts := []config.Option{
+		config.WithContext(ctx.Context()),
+		config.WithFlags(ctx.Flags),
+		config.WithHTTPClient(ctx.HTTPClient),
+		config.WithSHA256(ctx.ConfigSHA256),
+		config.WithProfile(ctx.Profile),
+	}
+	if tags := splitList(ctx.Tags); len(tags) > 0 {
+		opts = append(opts, config.WithTags(tags...))
+	}
+	if tags := splitList(ctx.ExcludeTags); len(tags) > 0 {
+		opts = append(opts, config.WithExcludeTags(tags...))
+	}
+	if names := splitList(ctx.Only); len(names) > 0 {
+		opts = append(opts, config.WithOnly(names...))
+	}
+	return opts
+}
+
+// loadConfig 加载配置：优先复用启动时已读取的配置，未读取时才从配置文件加载
+func loadConfig(ctx *command.Context, loader *config.Loader) (*config.Config, error) {
+	if ctx.Config != nil {
+		return loader.Select(ctx.Config)
+	}
+	return loader.Load(ctx.ConfigFile)
+}
+
+// splitList 拆分逗号分隔的列表，忽略空白项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// testerOptions 根据全局参数生成测试器配置（test 与 watch 共用）
+func testerOptions(ctx *command.Context) []tester.Option {
+	opts := []tester.Option{
+		tester.WithSamples(ctx.Samples),
+		tester.WithConcurrency(ctx.Concurrency),
+		tester.WithRateLimit(ctx.RateLimit),
+		tester.WithPerHostSerial(ctx.PerHost),
+	}
+	if ctx.DialContext != nil {
+		opts = append(opts, tester.WithDialContext(ctx.DialContext))
+	}
+	if ctx.ConnMode != "" {
+		opts = append(opts, tester.WithConnMode(tester.ConnMode(ctx.ConnMode)))
+	}
+	if ctx.Family != "" {
+		opts = append(opts, tester.WithFamily(tester.Family(ctx.Family)))
+	}
+	if ctx.CertWarnDays > 0 {
+		opts = append(opts, tester.WithCertWarnDays(ctx.CertWarnDays))
+	}
+	if ctx.Retries > 0 {
+		opts = append(opts, tester.WithRetryPolicy(tester.RetryPolicy{
+			Retries:   ctx.Retries,
+			BaseDelay: time.Duration(ctx.RetryDelay) * time.Millisecond,
+		}))
+	}
+	if n, err := tester.ParseRedirectPolicy(ctx.Redirect); err == nil && ctx.Redirect != "" {
+		opts = append(opts, tester.WithMaxRedirects(n))
+	}
+	return opts
+}
+
+// runTests 测试所有站点，每个站点完成即输出一行，全部完成后输出其余报告（test 与 watch 共用）
+func runTests(ctx *command.Context, t *tester.Tester, sites []tester.Site) []tester.TestResult {
+	results := make([]tester.TestResult, len(sites))
+	table := output.NewLiveTable(sites, ctx.Detail)
+	t.TestStream(ctx.Context(), sites, func(idx int, result tester.TestResult) {
+		results[idx] = result
+		table.Add(idx, result)
+	})
+	table.Close(results)
+
+	printReports(ctx, sites, results)
+	return results
+}
+
+// printReports 按全局参数输出结果表之后的统计表、证书报告和摘要
+func printReports(ctx *command.Context, sites []tester.Site, results []tester.TestResult) {
+	if ctx.Samples > 1 {
+		output.PrintStatsTable(results)
+	}
+	if ctx.ConnMode == string(tester.ConnBoth) {
+		output.PrintConnTable(results)
+	}
+	if ctx.Family == string(tester.FamilyBoth) {
+		output.PrintFamilyTable(results)
+	}
+	if ctx.CertReport {
+		output.PrintCertReport(results)
+	}
+	if ctx.GroupBy != "" {
+		output.PrintGroupTable(sites, results, ctx.GroupBy)
+	}
+	output.PrintSummary(results)
+}
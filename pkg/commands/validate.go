@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/icarus-go/netspeed/pkg/command"
+	"github.com/icarus-go/netspeed/pkg/config"
+)
+
+// ValidateConfigCommand 配置文件校验命令
+type ValidateConfigCommand struct {
+	enabled *bool
+}
+
+// NewValidateConfigCommand 创建配置文件校验命令
+func NewValidateConfigCommand() *ValidateConfigCommand {
+	return &ValidateConfigCommand{}
+}
+
+// Name 返回命令名称
+func (c *ValidateConfigCommand) Name() string {
+	return "validate-config"
+}
+
+// Description 返回命令描述
+func (c *ValidateConfigCommand) Description() string {
+	return "校验配置文件"
+}
+
+// DefineFlags 定义命令的 flag 参数
+func (c *ValidateConfigCommand) DefineFlags(flags *flag.FlagSet) {
+	c.enabled = flags.Bool("validate-config", false, "只校验配置文件（语法、字段、URL、重复项），不执行测试")
+}
+
+// Execute 执行命令，校验通过后不再执行其他命令
+func (c *ValidateConfigCommand) Execute(ctx *command.Context) error {
+	if !*c.enabled {
+		return nil
+	}
+	if ctx.ConfigFile == "" {
+		return fmt.Errorf("未指定配置文件（使用 -config 或环境变量 %s）", config.EnvName("config"))
+	}
+
+	// 启动时已读取并校验过配置时直接复用，只合并配置方案
+	cfg, err := loadConfig(ctx, config.NewLoader(
+		config.WithContext(ctx.Context()),
+		config.WithFlags(ctx.Flags),
+		config.WithHTTPClient(ctx.HTTPClient),
+		config.WithSHA256(ctx.ConfigSHA256),
+		config.WithProfile(ctx.Profile),
+	))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ %s 校验通过: %d 个站点, %d 个全局选项, %d 个 IP API 提供商, %d 个配置方案\n",
+		ctx.ConfigFile, len(cfg.Sites), len(cfg.Options), len(cfg.Providers), len(cfg.Profiles))
+	return command.ErrStop
+}
+
+// Priority 返回命令优先级
+func (c *ValidateConfigCommand) Priority() int {
+	return 2
+}
@@ -1,8 +1,14 @@
 package commands
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/icarus-go/netspeed/pkg/command"
@@ -47,33 +53,62 @@ func (c *WatchCommand) Execute(ctx *command.Context) error {
 	fmt.Println()
 
 	// 加载站点配置
-	loader := config.NewLoader()
-	sites, err := loader.LoadSites(ctx.ConfigFile)
+	loader := config.NewLoader(loaderOptions(ctx)...)
+	cfg, err := loadConfig(ctx, loader)
 	if err != nil {
 		return fmt.Errorf("加载配置失败: %v", err)
 	}
+	sites := cfg.Sites
+	options := cfg.Options // 启动时生效的全局选项，重新加载时不再改变
 
 	// 创建测试器
 	timeout := time.Duration(ctx.Timeout) * time.Second
-	t := tester.NewTester(ctx.HTTPClient, timeout)
+	t := tester.NewTester(ctx.HTTPClient, timeout, append(testerOptions(ctx), cfg.TesterOptions()...)...)
+
+	runCtx := ctx.Context()
+	session := output.NewSession()
+	watcher := newConfigWatcher(loader, ctx.ConfigFile, cfg)
 
 	// 首次立即执行
-	c.runTest(t, sites)
+	c.runTest(ctx, t, sites, session)
 
-	// 定期执行
+	// 定期执行，直到收到退出信号
 	ticker := time.NewTicker(time.Duration(*c.interval) * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		// 清屏（跨平台方式）
-		fmt.Print("\033[2J\033[H")
-
-		fmt.Printf("⏰ 最后更新: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-		fmt.Println()
-		c.runTest(t, sites)
+	for runCtx.Err() == nil {
+		select {
+		case <-runCtx.Done():
+		case <-ticker.C:
+			// 清屏（跨平台方式）
+			fmt.Print("\033[2J\033[H")
+
+			fmt.Printf("⏰ 最后更新: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+
+			// 配置文件有变化时重新加载，从本轮开始生效；无效的修改只提示，继续使用当前配置
+			if newCfg, err := watcher.poll(); err != nil {
+				fmt.Printf("⚠️  配置重新加载失败，继续使用当前配置: %v\n", err)
+			} else if newCfg != nil {
+				added, removed, changed := diffSites(sites, newCfg.Sites)
+				if changed > 0 {
+					fmt.Printf("🔄 config reloaded: +%d -%d sites (%d changed)\n", added, removed, changed)
+				} else {
+					fmt.Printf("🔄 config reloaded: +%d -%d sites\n", added, removed)
+				}
+				// 代理、超时等全局选项在启动时用于创建客户端和测试器，重新加载只更新站点和评级阈值
+				if keys := changedOptions(options, newCfg.Options); len(keys) > 0 {
+					fmt.Printf("⚠️  选项 %s 的修改需要重启后生效\n", strings.Join(keys, ", "))
+				}
+				sites = newCfg.Sites
+				t = tester.NewTester(ctx.HTTPClient, timeout, append(testerOptions(ctx), newCfg.TesterOptions()...)...)
+			}
+			fmt.Println()
+			c.runTest(ctx, t, sites, session)
+		}
 	}
 
-	return nil
+	output.PrintSessionSummary(session)
+	return runCtx.Err()
 }
 
 // Priority 返回命令优先级
@@ -81,13 +116,117 @@ func (c *WatchCommand) Priority() int {
 	return 30
 }
 
-// runTest 执行一次测试
-func (c *WatchCommand) runTest(t *tester.Tester, sites []tester.Site) {
+// runTest 执行一次测试，完整完成的一轮计入会话统计
+func (c *WatchCommand) runTest(ctx *command.Context, t *tester.Tester, sites []tester.Site, session *output.Session) {
 	fmt.Printf("🚀 开始测试 %d 个网站...\n", len(sites))
 	fmt.Println()
 
-	results := t.TestAll(sites)
+	results := runTests(ctx, t, sites)
+	if ctx.Context().Err() == nil {
+		session.Record(results)
+	}
+}
+
+// configWatcher 检测配置文件的变化
+// 本地文件先比较修改时间和大小，远程配置每轮重新验证（未修改时服务器返回 304），
+// 重新加载后再比较配置内容，只有内容变化时才视为新配置
+type configWatcher struct {
+	loader  *config.Loader
+	file    string
+	modTime time.Time
+	size    int64
+	digest  [sha256.Size]byte // 当前生效配置的摘要
+	lastErr string            // 上次重新加载的错误，相同的错误只报告一次
+}
+
+// newConfigWatcher 创建配置监视器，cfg 为当前生效的配置
+func newConfigWatcher(loader *config.Loader, file string, cfg *config.Config) *configWatcher {
+	w := &configWatcher{loader: loader, file: file, digest: configDigest(cfg)}
+	if file != "" && !config.IsRemote(file) {
+		w.statChanged()
+	}
+	return w
+}
+
+// poll 检查配置是否变化：变化且有效时返回新配置，没有变化时返回 nil，
+// 新配置无效时返回错误（同一错误只返回一次）
+func (w *configWatcher) poll() (*config.Config, error) {
+	if w.file == "" {
+		return nil, nil
+	}
+	if !config.IsRemote(w.file) && !w.statChanged() {
+		return nil, nil
+	}
+
+	cfg, err := w.loader.Load(w.file)
+	if err != nil {
+		if err.Error() == w.lastErr {
+			return nil, nil
+		}
+		w.lastErr = err.Error()
+		return nil, err
+	}
+	w.lastErr = ""
+
+	digest := configDigest(cfg)
+	if digest == w.digest {
+		return nil, nil
+	}
+	w.digest = digest
+	return cfg, nil
+}
+
+// statChanged 记录本地文件的修改时间和大小，返回与上次相比是否有变化
+// 文件暂时不可读（如编辑器保存时先删除再写入）时视为有变化，由重新加载报告错误
+func (w *configWatcher) statChanged() bool {
+	info, err := os.Stat(w.file)
+	if err != nil {
+		return true
+	}
+	changed := !info.ModTime().Equal(w.modTime) || info.Size() != w.size
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return changed
+}
 
-	output.PrintResultsTable(results)
-	output.PrintSummary(results)
+// configDigest 计算配置内容的摘要（按解析后的配置计算，忽略格式和注释的变化）
+func configDigest(cfg *config.Config) [sha256.Size]byte {
+	data, _ := json.Marshal(cfg)
+	return sha256.Sum256(data)
+}
+
+// diffSites 按名称统计新增和移除的站点数，以及名称相同但配置（URL、方法等）有变化的站点数
+func diffSites(old, updated []tester.Site) (added, removed, changed int) {
+	previous := make(map[string]tester.Site, len(old))
+	for _, site := range old {
+		previous[site.Name] = site
+	}
+	for _, site := range updated {
+		prev, ok := previous[site.Name]
+		switch {
+		case !ok:
+			added++
+			continue
+		case !reflect.DeepEqual(prev, site):
+			changed++
+		}
+		delete(previous, site.Name)
+	}
+	return added, len(previous), changed
+}
+
+// changedOptions 返回值有变化的全局选项名（按名称排序）
+func changedOptions(old, updated map[string]any) []string {
+	var keys []string
+	for key, value := range updated {
+		if prev, ok := old[key]; !ok || !reflect.DeepEqual(prev, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range old {
+		if _, ok := updated[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
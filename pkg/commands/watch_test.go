@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/command"
+	"github.com/icarus-go/netspeed/pkg/config"
+)
+
+// TestConfigWatcher 测试配置文件变化检测：有效修改返回新配置，无效修改只报告一次
+func TestConfigWatcher(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sites.json")
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		// 显式设置修改时间，避免文件系统时间精度导致检测不到变化
+		if err := os.Chtimes(file, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := time.Now().Add(-time.Hour)
+	write(`[{"Name": "A", "URL": "https://a.com"}, {"Name": "B", "URL": "https://b.com"}]`, base)
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newConfigWatcher(loader, file, cfg)
+
+	// 没有变化
+	if got, err := w.poll(); got != nil || err != nil {
+		t.Fatalf("poll() = %v, %v, want nil, nil", got, err)
+	}
+
+	// 只修改时间，内容不变
+	write(`[{"Name": "A", "URL": "https://a.com"}, {"Name": "B", "URL": "https://b.com"}]`, base.Add(time.Minute))
+	if got, err := w.poll(); got != nil || err != nil {
+		t.Fatalf("poll() after touch = %v, %v, want nil, nil", got, err)
+	}
+
+	// 有效修改：新增 C、D，移除 B
+	write(`[{"Name": "A", "URL": "https://a.com"}, {"Name": "C", "URL": "https://c.com"}, {"Name": "D", "URL": "https://d.com"}]`, base.Add(2*time.Minute))
+	updated, err := w.poll()
+	if err != nil || updated == nil {
+		t.Fatalf("poll() after edit = %v, %v, want new config", updated, err)
+	}
+	if added, removed, changed := diffSites(cfg.Sites, updated.Sites); added != 2 || removed != 1 || changed != 0 {
+		t.Errorf("diffSites() = +%d -%d ~%d, want +2 -1 ~0", added, removed, changed)
+	}
+
+	// 只修改 URL 也算作变化
+	write(`[{"Name": "A", "URL": "https://a2.com"}, {"Name": "C", "URL": "https://c.com"}, {"Name": "D", "URL": "https://d.com"}]`, base.Add(150*time.Second))
+	edited, err := w.poll()
+	if err != nil || edited == nil {
+		t.Fatalf("poll() after URL edit = %v, %v, want new config", edited, err)
+	}
+	if added, removed, changed := diffSites(updated.Sites, edited.Sites); added != 0 || removed != 0 || changed != 1 {
+		t.Errorf("diffSites() = +%d -%d ~%d, want +0 -0 ~1", added, removed, changed)
+	}
+
+	// 无效修改报告一次，之后不再重复
+	write(`[{"Name": "A", "URL": "ftp://a.com"}]`, base.Add(3*time.Minute))
+	if _, err := w.poll(); err == nil {
+		t.Fatal("poll() after invalid edit error = nil, want error")
+	}
+	if got, err := w.poll(); got != nil || err != nil {
+		t.Errorf("poll() repeated = %v, %v, want nil, nil", got, err)
+	}
+
+	// 改回有效内容后恢复
+	write(`[{"Name": "E", "URL": "https://e.com"}]`, base.Add(4*time.Minute))
+	if got, err := w.poll(); err != nil || got == nil || len(got.Sites) != 1 {
+		t.Errorf("poll() after fix = %v, %v, want 1 site", got, err)
+	}
+}
+
+// TestConfigWatcher_InvalidOptions 测试重新加载时与启动时一样校验全局选项，未知选项作为无效修改报告并保留原站点
+func TestConfigWatcher_InvalidOptions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sites.json")
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Int("timeout", 10, "")
+	ctx := &command.Context{Flags: flags, ConfigFile: file}
+
+	base := time.Now().Add(-time.Hour)
+	write(`{"Options": {"timeout": 5}, "Sites": [{"Name": "A", "URL": "https://a.com"}]}`, base)
+
+	loader := config.NewLoader(loaderOptions(ctx)...)
+	cfg, err := loadConfig(ctx, loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newConfigWatcher(loader, file, cfg)
+
+	write(`{"Options": {"timout": 5}, "Sites": [{"Name": "A", "URL": "https://a.com"}, {"Name": "B", "URL": "https://b.com"}]}`, base.Add(time.Minute))
+	got, err := w.poll()
+	if err == nil || !strings.Contains(err.Error(), "timout") {
+		t.Fatalf("poll() error = %v, want unknown option timout", err)
+	}
+	if got != nil {
+		t.Errorf("poll() = %+v, want nil so the previous sites are kept", got)
+	}
+	if len(cfg.Sites) != 1 || cfg.Sites[0].Name != "A" {
+		t.Errorf("Sites = %+v, want previous [A]", cfg.Sites)
+	}
+}
+
+// TestChangedOptions 测试找出有变化的全局选项
+func TestChangedOptions(t *testing.T) {
+	old := map[string]any{"timeout": float64(5), "proxy": "http://a:8080", "tags": []any{"cn"}}
+	updated := map[string]any{"timeout": float64(10), "tags": []any{"cn"}, "retries": float64(2)}
+
+	got := changedOptions(old, updated)
+	want := []string{"proxy", "retries", "timeout"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("changedOptions() = %v, want %v", got, want)
+	}
+	if got := changedOptions(old, old); len(got) != 0 {
+		t.Errorf("changedOptions(same) = %v, want none", got)
+	}
+}
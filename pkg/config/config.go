@@ -0,0 +1,241 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/ipinfo"
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// Config 配置文件内容
+// 配置文件支持 JSON、YAML、TOML 三种格式（按扩展名区分），可以是包含 Options、Sites 等字段的对象，
+// 也可以是站点数组（旧格式）
+type Config struct {
+	// Options 全局选项，键为命令行 flag 名（如 proxy、timeout、watch），优先级低于命令行和环境变量
+	Options map[string]any `json:"Options,omitempty"`
+
+	// Providers IP 检测使用的 API 提供商（可选），未配置时使用内置列表
+	Providers []ipinfo.Provider `json:"Providers,omitempty"`
+
+	// Thresholds 全局延迟评级阈值（可选），未配置的字段使用默认值
+	Thresholds *tester.Thresholds `json:"Thresholds,omitempty"`
+
+	// TagThresholds 按分组或标签设置的评级阈值（可选），优先于全局阈值，站点自身的阈值优先于它
+	TagThresholds map[string]tester.Thresholds `json:"TagThresholds,omitempty"`
+
+	// Sites 测试站点，未配置时使用默认站点
+	Sites []tester.Site `json:"Sites"`
+
+	// Profiles 命名配置方案（可选），通过 -profile 选择后合并到上面的设置
+	Profiles map[string]Profile `json:"Profiles,omitempty"`
+}
+
+// TesterOptions 返回配置文件中的全局设置对应的测试器配置项
+func (c *Config) TesterOptions() []tester.Option {
+	var opts []tester.Option
+	if c.Thresholds != nil {
+		opts = append(opts, tester.WithThresholds(*c.Thresholds))
+	}
+	return opts
+}
+
+// parseConfig 解析配置文件，兼容站点数组和对象两种格式
+func parseConfig(data []byte, format string) (*Config, error) {
+	data, err := toJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &cfg.Sites); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyTagThresholds 将分组和标签的评级阈值合并到站点上（按 Group、Tags 的顺序，后者覆盖前者）
+func (c *Config) applyTagThresholds() {
+	if len(c.TagThresholds) == 0 {
+		return
+	}
+
+	for i := range c.Sites {
+		site := &c.Sites[i]
+
+		var merged tester.Thresholds
+		matched := false
+		for _, tag := range append([]string{site.Group}, site.Tags...) {
+			if th, ok := c.TagThresholds[tag]; ok && tag != "" {
+				merged = merged.Merge(th)
+				matched = true
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		if site.Thresholds != nil {
+			merged = merged.Merge(*site.Thresholds)
+		}
+		site.Thresholds = &merged
+	}
+}
+
+// validate 检查配置中的评级阈值（合并默认值后需递增）
+func (c *Config) validate() []Problem {
+	var problems []Problem
+	global := tester.DefaultThresholds
+	if c.Thresholds != nil {
+		global = global.Merge(*c.Thresholds)
+		if err := global.Validate(); err != nil {
+			problems = append(problems, Problem{Path: "Thresholds", Message: err.Error()})
+		}
+	}
+
+	for i, site := range c.Sites {
+		if site.Thresholds == nil {
+			continue
+		}
+		if err := global.Merge(*site.Thresholds).Validate(); err != nil {
+			problems = append(problems, Problem{
+				Path:    fmt.Sprintf("Sites[%d].Thresholds", i),
+				Message: fmt.Sprintf("站点 %s: %v", site.Name, err),
+			})
+		}
+	}
+	return problems
+}
+
+// readConfig 从文件或 URL 读取、校验并解析配置，返回未合并配置方案的原始配置
+// 配置有误时返回包含全部问题的 *ValidationError
+func (l *Loader) readConfig(filename string) (*Config, error) {
+	data, err := l.readSource(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	format := formatOf(filename)
+	if u, err := url.Parse(filename); err == nil && IsRemote(filename) {
+		format = formatOf(u.Path)
+	}
+	root, syntaxErr := parseTree(data, format)
+	if syntaxErr != nil {
+		return nil, &ValidationError{File: filename, Problems: []Problem{*syntaxErr}}
+	}
+
+	v := &validator{loader: l, remote: IsRemote(filename)}
+	v.check(root)
+
+	cfg, err := parseConfig(data, format)
+	if err != nil {
+		if len(v.problems) == 0 {
+			v.add(nil, "", "解析配置文件失败: %v", err)
+		}
+		return nil, newValidationError(filename, v.problems)
+	}
+
+	// 本地配置中的相对路径相对于配置文件所在目录，与启动目录无关（远程配置不允许 BodyFile）
+	if !IsRemote(filename) {
+		if dir, err := filepath.Abs(filepath.Dir(filename)); err == nil {
+			cfg.resolvePaths(dir)
+		}
+	}
+
+	// 未配置 Sites 时使用默认站点
+	if cfg.Sites == nil {
+		// 复制一份，避免合并标签阈值时修改全局的默认站点
+		cfg.Sites = append([]tester.Site(nil), tester.DefaultSites...)
+	}
+	for i := range cfg.Providers {
+		if cfg.Providers[i].Format == "" {
+			cfg.Providers[i].Format = "json"
+		}
+	}
+
+	// 按合并选中的配置方案后的设置校验；继承关系有误时与其他问题一起报告
+	profile := l.profile
+	profileProblems := cfg.validateProfiles()
+	v.problems = append(v.problems, locateAll(root, profileProblems)...)
+	if len(profileProblems) > 0 {
+		profile = ""
+	}
+	checked, err := cfg.withProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	v.problems = append(v.problems, locateAll(root, checked.validate())...)
+
+	if len(v.problems) > 0 {
+		return nil, newValidationError(filename, v.problems)
+	}
+	return cfg, nil
+}
+
+// resolvePaths 将站点（包括配置方案中的站点）的相对 BodyFile 解析为 dir 下的路径
+func (c *Config) resolvePaths(dir string) {
+	resolve := func(sites []tester.Site) {
+		for i := range sites {
+			if file := sites[i].BodyFile; file != "" && !filepath.IsAbs(file) {
+				sites[i].BodyFile = filepath.Join(dir, file)
+			}
+		}
+	}
+	resolve(c.Sites)
+	for _, p := range c.Profiles {
+		resolve(p.Sites)
+	}
+}
+
+// withProfile 返回合并配置方案（name 为空时不合并）和标签阈值后的副本，不修改 c
+func (c *Config) withProfile(name string) (*Config, error) {
+	merged := *c
+	if name != "" {
+		if err := merged.applyProfile(name); err != nil {
+			return nil, err
+		}
+	}
+	merged.Sites = slices.Clone(merged.Sites)
+	merged.applyTagThresholds()
+	return &merged, nil
+}
+
+// locateAll 按字段路径为问题补充行列号
+func locateAll(root *node, problems []Problem) []Problem {
+	for i := range problems {
+		if n := locate(root, problems[i].Path); n != nil {
+			problems[i].Line, problems[i].Column = n.line, n.col
+		}
+	}
+	return problems
+}
+
+// locate 查找字段路径对应的节点，找不到时退回到最近的上级节点（兼容旧的站点数组格式）
+func locate(root *node, path string) *node {
+	if root.kind == kindArray {
+		path = strings.TrimPrefix(path, "Sites")
+	}
+	for path != "" {
+		if n := root.find(path); n != nil {
+			return n
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return nil
+}
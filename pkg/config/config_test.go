@@ -0,0 +1,504 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// TestLoader_Load 测试站点数组和对象两种配置格式
+func TestLoader_Load(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantSites      int
+		wantThresholds bool
+		wantErr        bool
+	}{
+		{
+			name:      "站点数组",
+			content:   `[{"Name": "A", "URL": "https://a.com"}, {"Name": "B", "URL": "https://b.com"}]`,
+			wantSites: 2,
+		},
+		{
+			name: "对象",
+			content: `{"Thresholds": {"Excellent": 20, "Good": 50, "Fair": 100},
+			  "Sites": [{"Name": "A", "URL": "https://a.com", "Thresholds": {"Fair": 80}}]}`,
+			wantSites:      1,
+			wantThresholds: true,
+		},
+		{
+			name:    "阈值不递增",
+			content: `{"Thresholds": {"Excellent": 800}, "Sites": [{"Name": "A", "URL": "https://a.com"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "站点阈值不递增",
+			content: `{"Sites": [{"Name": "A", "URL": "https://a.com", "Thresholds": {"Fair": 100}}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "没有站点",
+			content: `{"Sites": []}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "sites.json")
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := NewLoader().Load(file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(cfg.Sites) != tt.wantSites {
+				t.Errorf("len(Sites) = %d, want %d", len(cfg.Sites), tt.wantSites)
+			}
+			if (cfg.Thresholds != nil) != tt.wantThresholds {
+				t.Errorf("Thresholds = %v, want set %v", cfg.Thresholds, tt.wantThresholds)
+			}
+		})
+	}
+}
+
+// TestLoader_Filter 测试按标签、分组和名称筛选站点
+func TestLoader_Filter(t *testing.T) {
+	content := `[
+	  {"Name": "Baidu", "URL": "https://www.baidu.com", "Group": "domestic"},
+	  {"Name": "Google", "URL": "https://www.google.com", "Group": "overseas", "Tags": ["search"]},
+	  {"Name": "OpenAI", "URL": "https://api.openai.com", "Group": "overseas", "Tags": ["ai"]},
+	  {"Name": "Wiki", "URL": "https://wiki.internal", "Tags": ["internal"]}
+	]`
+	file := filepath.Join(t.TempDir(), "sites.json")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    []string
+		wantErr bool
+	}{
+		{"不筛选", nil, []string{"Baidu", "Google", "OpenAI", "Wiki"}, false},
+		{"按分组", []Option{WithTags("overseas")}, []string{"Google", "OpenAI"}, false},
+		{"按标签", []Option{WithTags("AI", "internal")}, []string{"OpenAI", "Wiki"}, false},
+		{"排除", []Option{WithTags("overseas"), WithExcludeTags("ai")}, []string{"Google"}, false},
+		{"按名称", []Option{WithOnly("baidu", "Wiki")}, []string{"Baidu", "Wiki"}, false},
+		{"没有匹配", []Option{WithOnly("Bing")}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sites, err := NewLoader(tt.opts...).LoadSites(file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSites() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var names []string
+			for _, site := range sites {
+				names = append(names, site.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("sites = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoader_TagThresholds 测试按分组和标签设置的评级阈值
+func TestLoader_TagThresholds(t *testing.T) {
+	content := `{
+	  "TagThresholds": {"domestic": {"Excellent": 20, "Good": 50, "Fair": 100}, "slow": {"Fair": 300}},
+	  "Sites": [
+	    {"Name": "A", "URL": "https://a.com", "Group": "domestic"},
+	    {"Name": "B", "URL": "https://b.com", "Group": "domestic", "Tags": ["slow"], "Thresholds": {"Excellent": 30}},
+	    {"Name": "C", "URL": "https://c.com"}
+	  ]
+	}`
+	file := filepath.Join(t.TempDir(), "sites.json")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader().Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*tester.Thresholds{
+		{Excellent: 20, Good: 50, Fair: 100},
+		{Excellent: 30, Good: 50, Fair: 300},
+		nil,
+	}
+	for i, site := range cfg.Sites {
+		if (site.Thresholds == nil) != (want[i] == nil) || (site.Thresholds != nil && *site.Thresholds != *want[i]) {
+			t.Errorf("%s: Thresholds = %+v, want %+v", site.Name, site.Thresholds, want[i])
+		}
+	}
+}
+
+// TestLoader_Formats 测试 JSON、YAML、TOML 三种格式的完整配置
+func TestLoader_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "JSON",
+			file: "netspeed.json",
+			content: `{"Options": {"timeout": 5, "tags": ["ai", "search"]},
+			  "Providers": [{"Name": "local", "URL": "http://127.0.0.1/ip", "Format": "text"}],
+			  "Thresholds": {"Fair": 800},
+			  "Sites": [{"Name": "A", "URL": "https://a.com"}]}`,
+		},
+		{
+			name: "YAML",
+			file: "netspeed.yaml",
+			content: `options:
+  timeout: 5
+  tags: [ai, search]
+providers:
+  - name: local
+    url: http://127.0.0.1/ip
+    format: text
+thresholds:
+  fair: 800
+sites:
+  - name: A
+    url: https://a.com
+`,
+		},
+		{
+			name: "TOML",
+			file: "netspeed.toml",
+			content: `[options]
+timeout = 5
+tags = ["ai", "search"]
+
+[[providers]]
+name = "local"
+url = "http://127.0.0.1/ip"
+format = "text"
+
+[thresholds]
+fair = 800
+
+[[sites]]
+name = "A"
+url = "https://a.com"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := NewLoader().Load(file)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(cfg.Sites) != 1 || cfg.Sites[0].URL != "https://a.com" {
+				t.Errorf("Sites = %+v", cfg.Sites)
+			}
+			if len(cfg.Providers) != 1 || cfg.Providers[0].Format != "text" {
+				t.Errorf("Providers = %+v", cfg.Providers)
+			}
+			if cfg.Thresholds == nil || cfg.Thresholds.Fair != 800 {
+				t.Errorf("Thresholds = %+v", cfg.Thresholds)
+			}
+			if got := formatOption(cfg.Options["timeout"]); got != "5" {
+				t.Errorf("Options[timeout] = %q, want 5", got)
+			}
+			if got := formatOption(cfg.Options["tags"]); got != "ai,search" {
+				t.Errorf("Options[tags] = %q, want ai,search", got)
+			}
+		})
+	}
+}
+
+// TestLoader_DefaultSites 测试只包含全局选项的配置文件使用默认站点
+func TestLoader_DefaultSites(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "netspeed.yaml")
+	if err := os.WriteFile(file, []byte("options:\n  proxy: socks5://127.0.0.1:1080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader().Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Sites) != len(tester.DefaultSites) {
+		t.Errorf("len(Sites) = %d, want %d", len(cfg.Sites), len(tester.DefaultSites))
+	}
+}
+
+// TestApplyOptions 测试 命令行 > 环境变量 > 配置文件 > 默认值 的优先级，以及加载配置文件前的 Lookup
+func TestApplyOptions(t *testing.T) {
+	newFlags := func() (*flag.FlagSet, *string, *int, *int, *bool) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		proxy := fs.String("proxy", "", "")
+		timeout := fs.Int("timeout", 10, "")
+		samples := fs.Int("samples", 1, "")
+		detail := fs.Bool("detail", false, "")
+		return fs, proxy, timeout, samples, detail
+	}
+	options := map[string]any{"proxy": "http://file:8080", "timeout": float64(5), "samples": float64(3)}
+
+	t.Setenv(EnvName("timeout"), "7")
+
+	fs, proxy, timeout, samples, detail := newFlags()
+	if err := fs.Parse([]string{"-proxy", "http://flag:8080"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 加载配置文件前只看命令行和环境变量，不修改 flag
+	if got := Lookup(fs, "proxy"); got != "http://flag:8080" {
+		t.Errorf("Lookup(proxy) = %q, want flag value", got)
+	}
+	if got := Lookup(fs, "timeout"); got != "7" || *timeout != 10 {
+		t.Errorf("Lookup(timeout) = %q, timeout = %d, want env value 7 and unchanged flag", got, *timeout)
+	}
+	if got := Lookup(fs, "samples"); got != "1" {
+		t.Errorf("Lookup(samples) = %q, want default 1", got)
+	}
+	if err := ApplyOptions(fs, options); err != nil {
+		t.Fatalf("ApplyOptions() error = %v", err)
+	}
+
+	if *proxy != "http://flag:8080" {
+		t.Errorf("proxy = %q, want flag value", *proxy)
+	}
+	if *timeout != 7 {
+		t.Errorf("timeout = %d, want env value 7", *timeout)
+	}
+	if *samples != 3 {
+		t.Errorf("samples = %d, want file value 3", *samples)
+	}
+	if *detail {
+		t.Errorf("detail = true, want default false")
+	}
+
+	fs, _, _, _, _ = newFlags()
+	if err := ApplyOptions(fs, map[string]any{"timout": float64(5)}); err == nil {
+		t.Error("ApplyOptions() 未知配置项应返回错误")
+	}
+}
+
+// TestLoader_Validate 测试校验时报告全部问题及其位置
+func TestLoader_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string // 期望的问题（Problem.String() 的形式）
+	}{
+		{
+			name:    "JSON 语法错误",
+			file:    "sites.json",
+			content: "[\n  {\"Name\": \"A\" \"URL\": \"https://a.com\"}\n]",
+			want:    []string{"2:16: 语法错误: invalid character '\"' after object key:value pair"},
+		},
+		{
+			name:    "TOML 语法错误",
+			file:    "netspeed.toml",
+			content: "[[sites]]\nname = \n",
+			want:    []string{"2:8: 语法错误: expected value but found '\\n' instead"},
+		},
+		{
+			name: "字段与站点问题",
+			file: "sites.json",
+			content: `[
+  {"Name": "A", "URL": "https://a.com", "Methd": "POST"},
+  {"Name": "a", "URL": "ftp://b.com"},
+  {"Name": "C", "URL": "https://a.com", "Timeout": "5"},
+  {"URL": "https:///x"}
+]`,
+			want: []string{
+				`2:41: Sites[0].Methd: 未知字段 "Methd"`,
+				`3:12: Sites[1].Name: 名称 "a" 与 Sites[0] 重复`,
+				`3:24: Sites[1].URL: 不支持的协议 ftp（支持 http, https, tcp, tls, dns）`,
+				`4:24: Sites[2].URL: URL 与 Sites[0] 重复`,
+				`4:52: Sites[2].Timeout: 应为数字`,
+				`5:3: Sites[3]: 缺少 Name`,
+				`5:11: Sites[3].URL: URL 缺少主机名: https:///x`,
+			},
+		},
+		{
+			name: "YAML 对象",
+			file: "netspeed.yaml",
+			content: `options:
+  timout: 5
+thresholds:
+  excellent: 800
+sites:
+  - name: A
+    url: a.com
+  - name: B
+    url: https://b.com
+    method: POST
+  - name: C
+    url: https://b.com
+`,
+			want: []string{
+				`2:3: Options.timout: 未知的配置项 "timout"`,
+				`4:3: Thresholds: 评级阈值需满足 Excellent < Good < Fair: 800/500/1000`,
+				`7:10: Sites[0].URL: URL 缺少协议（如 https://）: a.com`,
+			},
+		},
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("timeout", 10, "")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := NewLoader(WithFlags(fs)).Validate(file)
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+
+			var got []string
+			for _, p := range verr.Problems {
+				got = append(got, p.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Problems =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+// TestLoader_Profile 测试配置方案的继承与覆盖
+func TestLoader_Profile(t *testing.T) {
+	content := `{
+  "Options": {"timeout": 10, "samples": 3},
+  "Thresholds": {"Excellent": 150},
+  "Sites": [{"Name": "A", "URL": "https://a.com"}, {"Name": "B", "URL": "https://b.com"}],
+  "Profiles": {
+    "office": {"Options": {"proxy": "http://proxy:3128", "timeout": 5}, "Thresholds": {"Fair": 1500}},
+    "intranet": {"Extends": "office", "Options": {"tags": "intranet"},
+                 "Sites": [{"Name": "Wiki", "URL": "https://wiki.corp", "Tags": ["intranet"]}]},
+    "loop-a": {"Extends": "loop-b"},
+    "loop-b": {"Extends": "loop-a"}
+  }
+}`
+	file := filepath.Join(t.TempDir(), "netspeed.json")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 循环继承作为配置问题报告
+	_, err := NewLoader(WithProfile("intranet")).Load(file)
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Problems) != 2 || !strings.Contains(verr.Problems[0].Message, "循环继承") {
+		t.Fatalf("Load() error = %v, want 2 cyclic inheritance problems", err)
+	}
+
+	// 去掉循环继承后再测试合并结果
+	content = strings.Replace(content, `"loop-b": {"Extends": "loop-a"}`, `"loop-b": {}`, 1)
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader(WithProfile("intranet")).Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := FormatOptions(cfg.Options); got != "proxy=http://proxy:3128 samples=3 tags=intranet timeout=5" {
+		t.Errorf("Options = %s", got)
+	}
+	if cfg.Thresholds == nil || cfg.Thresholds.Excellent != 150 || cfg.Thresholds.Fair != 1500 {
+		t.Errorf("Thresholds = %+v, want Excellent 150, Fair 1500", cfg.Thresholds)
+	}
+	if len(cfg.Sites) != 1 || cfg.Sites[0].Name != "Wiki" {
+		t.Errorf("Sites = %+v, want [Wiki]", cfg.Sites)
+	}
+
+	// 不选择方案时使用顶层设置
+	if cfg, err = NewLoader().Load(file); err != nil || len(cfg.Sites) != 2 || FormatOptions(cfg.Options) != "samples=3 timeout=10" {
+		t.Errorf("Load() without profile = %+v, %v", cfg, err)
+	}
+
+	if _, err := NewLoader(WithProfile("home")).Load(file); err == nil || !strings.Contains(err.Error(), "未知的配置方案") {
+		t.Errorf("Load() unknown profile error = %v", err)
+	}
+
+	// Validate 返回原始配置，Select 合并方案时不修改原始配置
+	loader := NewLoader(WithProfile("intranet"), WithOnly("wiki"))
+	raw, err := loader.Validate(file)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if cfg, err = loader.Select(raw); err != nil || len(cfg.Sites) != 1 || cfg.Options["proxy"] != "http://proxy:3128" {
+		t.Errorf("Select() = %+v, %v", cfg, err)
+	}
+	if len(raw.Sites) != 2 || FormatOptions(raw.Options) != "samples=3 timeout=10" || raw.Thresholds.Fair != 0 {
+		t.Errorf("Validate() result modified by Select(): %+v", raw)
+	}
+}
+
+// TestLoader_BodyFileRelative 测试 BodyFile 的相对路径相对于配置文件所在目录，与启动目录无关
+func TestLoader_BodyFileRelative(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "body.json"), []byte(`{"check":"deep"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content := `{
+  "Sites": [{"Name": "A", "URL": "https://a.com", "BodyFile": "body.json"}],
+  "Profiles": {"deep": {"Sites": [{"Name": "B", "URL": "https://b.com", "BodyFile": "body.json"}]}}
+}`
+	if err := os.WriteFile(filepath.Join(dir, "netspeed.json"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 从其他目录以相对路径加载
+	t.Chdir(filepath.Dir(dir))
+	file := filepath.Join(filepath.Base(dir), "netspeed.json")
+	other := t.TempDir()
+	want := filepath.Join(dir, "body.json")
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		want = filepath.Join(real, "body.json")
+	}
+
+	loader := NewLoader(WithProfile("deep"))
+	raw, err := loader.Validate(file)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	// 加载后切换工作目录，路径仍然有效（如持续监控期间）
+	t.Chdir(other)
+	cfg, err := loader.Select(raw)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	for _, site := range append(raw.Sites, cfg.Sites...) {
+		got, _ := filepath.EvalSymlinks(site.BodyFile)
+		if got != want {
+			t.Errorf("%s BodyFile = %s, want %s", site.Name, site.BodyFile, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置文件格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// formatOf 根据扩展名判断配置文件格式，无法识别时按 JSON 处理
+func formatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// toJSON 将 YAML/TOML 配置转换为等价的 JSON，使三种格式共用同一套字段定义
+// （encoding/json 匹配字段名时不区分大小写，YAML/TOML 中可以写 sites 或 Sites）
+func toJSON(data []byte, format string) ([]byte, error) {
+	var doc any
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		table := map[string]any{}
+		if _, err := toml.Decode(string(data), &table); err != nil {
+			return nil, err
+		}
+		doc = table
+	default:
+		return data, nil
+	}
+
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("转换 %s 失败: %v", format, err)
+	}
+	return out, nil
+}
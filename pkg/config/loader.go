@@ -1,47 +1,179 @@
 package config
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"os"
+	"net/http"
+	"strings"
 
 	"github.com/icarus-go/netspeed/pkg/tester"
 )
 
 // Loader 配置加载器
-type Loader struct{}
+type Loader struct {
+	tags        []string        // 只保留带有任一标签的站点
+	excludeTags []string        // 排除带有任一标签的站点
+	only        []string        // 只保留指定名称的站点
+	flags       *flag.FlagSet   // 用于校验配置文件中的全局选项
+	client      *http.Client    // 获取远程配置使用的客户端
+	cacheDir    string          // 远程配置的缓存目录
+	sha256      string          // 配置内容的 SHA-256（十六进制小写）
+	profile     string          // 使用的配置方案
+	ctx         context.Context // 获取远程配置的上下文，取消时中断下载
+}
+
+// Option 配置加载器选项
+type Option func(*Loader)
+
+// WithTags 只加载 Tags 或 Group 包含任一标签的站点
+func WithTags(tags ...string) Option {
+	return func(l *Loader) {
+		l.tags = tags
+	}
+}
+
+// WithExcludeTags 排除 Tags 或 Group 包含任一标签的站点
+func WithExcludeTags(tags ...string) Option {
+	return func(l *Loader) {
+		l.excludeTags = tags
+	}
+}
+
+// WithOnly 只加载指定名称的站点（不区分大小写）
+func WithOnly(names ...string) Option {
+	return func(l *Loader) {
+		l.only = names
+	}
+}
+
+// WithFlags 校验配置文件 Options 中的键是否为 flags 中已定义的 flag
+func WithFlags(flags *flag.FlagSet) Option {
+	return func(l *Loader) {
+		l.flags = flags
+	}
+}
+
+// WithHTTPClient 设置获取远程配置使用的 HTTP 客户端（通常为已配置代理的客户端）
+func WithHTTPClient(client *http.Client) Option {
+	return func(l *Loader) {
+		l.client = client
+	}
+}
+
+// WithCacheDir 设置远程配置的缓存目录，默认为 DefaultCacheDir()
+func WithCacheDir(dir string) Option {
+	return func(l *Loader) {
+		l.cacheDir = dir
+	}
+}
+
+// WithSHA256 要求配置内容的 SHA-256 与 sum 一致（可带 sha256: 前缀），为空时不校验
+func WithSHA256(sum string) Option {
+	return func(l *Loader) {
+		l.sha256 = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(sum), "sha256:"))
+	}
+}
+
+// WithProfile 加载时合并指定的配置方案（为空时不使用）
+func WithProfile(name string) Option {
+	return func(l *Loader) {
+		l.profile = name
+	}
+}
+
+// WithContext 设置获取远程配置的上下文（如收到 Ctrl+C 时取消的上下文）
+func WithContext(ctx context.Context) Option {
+	return func(l *Loader) {
+		l.ctx = ctx
+	}
+}
+
+// Validate 读取并校验配置文件（包括选中的配置方案），返回的 *ValidationError 包含发现的全部问题
+// 返回未合并配置方案、未筛选站点的原始配置，可通过 Select 得到实际生效的配置，避免重复读取
+func (l *Loader) Validate(configFile string) (*Config, error) {
+	return l.readConfig(configFile)
+}
 
 // NewLoader 创建新的配置加载器
-func NewLoader() *Loader {
-	return &Loader{}
+func NewLoader(opts ...Option) *Loader {
+	l := &Loader{}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
-// LoadSites 加载测试站点
-func (l *Loader) LoadSites(configFile string) ([]tester.Site, error) {
+// Load 加载完整配置，未指定配置文件时使用默认站点，并合并配置方案、按筛选条件过滤站点
+func (l *Loader) Load(configFile string) (*Config, error) {
 	if configFile == "" {
 		// 返回默认站点
-		return tester.DefaultSites, nil
+		return l.Select(&Config{Sites: tester.DefaultSites})
 	}
 
 	// 从配置文件加载
-	return l.loadSitesFromFile(configFile)
+	cfg, err := l.readConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return l.Select(cfg)
 }
 
-// loadSitesFromFile 从 JSON 配置文件加载站点
-func (l *Loader) loadSitesFromFile(filename string) ([]tester.Site, error) {
-	data, err := os.ReadFile(filename)
+// Select 对已读取的配置（Validate 的结果）合并配置方案并按筛选条件过滤站点，返回新的配置，不修改 cfg
+func (l *Loader) Select(cfg *Config) (*Config, error) {
+	selected, err := cfg.withProfile(l.profile)
 	if err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+		return nil, err
 	}
+	if !l.filtering() {
+		return selected, nil
+	}
+
+	selected.Sites = l.filter(selected.Sites)
+	if len(selected.Sites) == 0 {
+		return nil, fmt.Errorf("没有符合筛选条件的站点")
+	}
+	return selected, nil
+}
 
-	var sites []tester.Site
-	if err := json.Unmarshal(data, &sites); err != nil {
-		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+// LoadSites 加载测试站点
+func (l *Loader) LoadSites(configFile string) ([]tester.Site, error) {
+	cfg, err := l.Load(configFile)
+	if err != nil {
+		return nil, err
 	}
+	return cfg.Sites, nil
+}
+
+// filtering 是否设置了筛选条件
+func (l *Loader) filtering() bool {
+	return len(l.tags) > 0 || len(l.excludeTags) > 0 || len(l.only) > 0
+}
 
-	if len(sites) == 0 {
-		return nil, fmt.Errorf("配置文件中没有站点")
+// filter 按筛选条件过滤站点，返回新的切片
+func (l *Loader) filter(sites []tester.Site) []tester.Site {
+	var matched []tester.Site
+	for _, site := range sites {
+		if len(l.only) > 0 && !containsFold(l.only, site.Name) {
+			continue
+		}
+		if len(l.tags) > 0 && !site.HasAnyTag(l.tags) {
+			continue
+		}
+		if site.HasAnyTag(l.excludeTags) {
+			continue
+		}
+		matched = append(matched, site)
 	}
+	return matched
+}
 
-	return sites, nil
+// containsFold 列表中是否包含 s（不区分大小写）
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix 环境变量前缀，flag 名转为大写并把 - 换成 _，如 -cert-warn-days 对应 NETSPEED_CERT_WARN_DAYS
+const EnvPrefix = "NETSPEED_"
+
+// nonFileFlags 不能通过配置文件设置的 flag
+var nonFileFlags = map[string]bool{
+	"config":          true, // 配置文件路径本身
+	"config-sha256":   true, // 配置文件不能校验自身
+	"profile":         true, // 配置方案在加载配置文件时就已合并
+	"help":            true,
+	"list-profiles":   true, // 只用于命令行的操作
+	"validate-config": true,
+}
+
+// EnvName 返回 flag 对应的环境变量名
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// ApplyOptions 为命令行中未指定的 flag 赋值，优先级为 命令行 > 环境变量 > 配置文件 > 默认值
+// options 为配置文件 Options 中的内容，键为 flag 名
+func ApplyOptions(fs *flag.FlagSet, options map[string]any) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	// 先检查配置文件中的未知选项，避免拼写错误被静默忽略
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if fs.Lookup(name) == nil || nonFileFlags[name] {
+			return fmt.Errorf("未知的配置项: %s", name)
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || explicit[f.Name] || f.Name == "config" {
+			return
+		}
+
+		if value, ok := os.LookupEnv(EnvName(f.Name)); ok {
+			if e := fs.Set(f.Name, value); e != nil {
+				err = fmt.Errorf("环境变量 %s 无效: %v", EnvName(f.Name), e)
+			}
+			return
+		}
+
+		if value, ok := options[f.Name]; ok && value != nil {
+			if e := fs.Set(f.Name, formatOption(value)); e != nil {
+				err = fmt.Errorf("配置项 %s 无效: %v", f.Name, e)
+			}
+		}
+	})
+	return err
+}
+
+// Lookup 返回 flag 按 命令行 > 环境变量 > 默认值 确定的值，不修改 fs
+// 用于在加载配置文件之前确定配置文件路径、代理等引导参数
+func Lookup(fs *flag.FlagSet, name string) string {
+	f := fs.Lookup(name)
+	if f == nil {
+		return ""
+	}
+
+	explicit := false
+	fs.Visit(func(v *flag.Flag) {
+		explicit = explicit || v.Name == name
+	})
+	if !explicit {
+		if value, ok := os.LookupEnv(EnvName(name)); ok {
+			return value
+		}
+	}
+	return f.Value.String()
+}
+
+// formatOption 将配置文件中的值转换为 flag 能解析的字符串，列表按逗号拼接
+func formatOption(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatOption(item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// FormatOptions 返回按键排序的 key=value 形式的选项列表，用于展示
+func FormatOptions(options map[string]any) string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = key + "=" + formatOption(options[key])
+	}
+	return strings.Join(items, " ")
+}
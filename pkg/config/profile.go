@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// Profile 命名配置方案，用于在"公司代理"、"家里 socks5"、"直连"等环境之间切换
+// 通过 Extends 继承另一个方案，子方案中设置的字段覆盖父方案
+type Profile struct {
+	// Description 方案说明（可选）
+	Description string `json:"Description,omitempty"`
+
+	// Extends 继承的方案名称（可选）
+	Extends string `json:"Extends,omitempty"`
+
+	// Options 全局选项（如 proxy、timeout、tags），逐项覆盖顶层 Options
+	Options map[string]any `json:"Options,omitempty"`
+
+	// Thresholds 评级阈值，逐项覆盖顶层 Thresholds
+	Thresholds *tester.Thresholds `json:"Thresholds,omitempty"`
+
+	// Sites 测试站点，设置后替换顶层 Sites
+	Sites []tester.Site `json:"Sites,omitempty"`
+}
+
+// ProfileNames 返回按名称排序的配置方案列表
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveProfile 沿继承链合并配置方案，返回合并后的方案（Extends 为直接父方案）
+func (c *Config) ResolveProfile(name string) (*Profile, error) {
+	var chain []Profile
+	seen := make(map[string]bool)
+	for current := name; current != ""; {
+		if seen[current] {
+			return nil, fmt.Errorf("配置方案 %s 存在循环继承", name)
+		}
+		seen[current] = true
+
+		p, ok := c.Profiles[current]
+		if !ok {
+			if current == name {
+				return nil, fmt.Errorf("未知的配置方案: %s (可用: %s)", name, c.availableProfiles())
+			}
+			return nil, fmt.Errorf("配置方案 %s 继承的 %s 不存在", name, current)
+		}
+		chain = append(chain, p)
+		current = p.Extends
+	}
+
+	// 从最顶层的父方案开始，逐级覆盖
+	resolved := &Profile{Extends: chain[0].Extends, Options: make(map[string]any)}
+	for i := len(chain) - 1; i >= 0; i-- {
+		p := chain[i]
+		if p.Description != "" {
+			resolved.Description = p.Description
+		}
+		for key, value := range p.Options {
+			resolved.Options[key] = value
+		}
+		if p.Thresholds != nil {
+			var merged tester.Thresholds
+			if resolved.Thresholds != nil {
+				merged = *resolved.Thresholds
+			}
+			merged = merged.Merge(*p.Thresholds)
+			resolved.Thresholds = &merged
+		}
+		if p.Sites != nil {
+			resolved.Sites = p.Sites
+		}
+	}
+	return resolved, nil
+}
+
+// applyProfile 将配置方案合并到顶层配置
+func (c *Config) applyProfile(name string) error {
+	p, err := c.ResolveProfile(name)
+	if err != nil {
+		return err
+	}
+
+	options := make(map[string]any, len(c.Options)+len(p.Options))
+	for key, value := range c.Options {
+		options[key] = value
+	}
+	for key, value := range p.Options {
+		options[key] = value
+	}
+	c.Options = options
+
+	if p.Thresholds != nil {
+		var merged tester.Thresholds
+		if c.Thresholds != nil {
+			merged = *c.Thresholds
+		}
+		merged = merged.Merge(*p.Thresholds)
+		c.Thresholds = &merged
+	}
+	if p.Sites != nil {
+		c.Sites = p.Sites
+	}
+	return nil
+}
+
+// validateProfiles 检查所有配置方案的继承关系和评级阈值
+func (c *Config) validateProfiles() []Problem {
+	var problems []Problem
+	for _, name := range c.ProfileNames() {
+		path := joinPath("Profiles", name)
+		p, err := c.ResolveProfile(name)
+		if err != nil {
+			problems = append(problems, Problem{Path: joinPath(path, "Extends"), Message: err.Error()})
+			continue
+		}
+		if p.Thresholds == nil {
+			continue
+		}
+
+		global := tester.DefaultThresholds
+		if c.Thresholds != nil {
+			global = global.Merge(*c.Thresholds)
+		}
+		if err := global.Merge(*p.Thresholds).Validate(); err != nil {
+			problems = append(problems, Problem{Path: joinPath(path, "Thresholds"), Message: err.Error()})
+		}
+	}
+	return problems
+}
+
+// availableProfiles 返回可用方案名称列表，用于错误提示
+func (c *Config) availableProfiles() string {
+	if len(c.Profiles) == 0 {
+		return "配置文件中没有配置方案"
+	}
+	return strings.Join(c.ProfileNames(), ", ")
+}
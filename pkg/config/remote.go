@@ -0,0 +1,256 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRemoteConfig 远程配置文件的大小上限
+const maxRemoteConfig = 10 << 20
+
+// offlineWarned 已提示过使用缓存的 URL
+var offlineWarned sync.Map
+
+// remoteMeta 远程配置缓存的元数据，用于 ETag/Last-Modified 重新验证
+type remoteMeta struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// IsRemote 配置文件路径是否为 http(s) URL
+func IsRemote(configFile string) bool {
+	u, err := url.Parse(configFile)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return (scheme == "https" || scheme == "http") && u.Host != ""
+}
+
+// DefaultCacheDir 远程配置的默认缓存目录（用户缓存目录下的 netspeed/config）
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "netspeed", "config")
+}
+
+// readSource 读取配置内容：本地文件直接读取，URL 经由 HTTP 获取（带磁盘缓存）
+// 配置了 SHA-256 时校验内容
+func (l *Loader) readSource(configFile string) ([]byte, error) {
+	var data []byte
+	var err error
+	if IsRemote(configFile) {
+		data, err = l.fetchRemote(configFile)
+	} else if data, err = os.ReadFile(configFile); err != nil {
+		err = fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.verifySHA256(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// verifySHA256 校验配置内容的 SHA-256，未设置时跳过
+func (l *Loader) verifySHA256(data []byte) error {
+	if l.sha256 == "" {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != l.sha256 {
+		return fmt.Errorf("配置文件 SHA-256 校验失败: 期望 %s, 实际 %s", l.sha256, got)
+	}
+	return nil
+}
+
+// fetchRemote 获取远程配置：有缓存时带上 ETag/Last-Modified 重新验证，
+// 服务器返回 304 时使用缓存，网络不可用或服务器出错时回退到缓存
+func (l *Loader) fetchRemote(rawURL string) ([]byte, error) {
+	if err := l.checkPlainHTTP(rawURL); err != nil {
+		return nil, err
+	}
+
+	dataPath, metaPath := l.cachePaths(rawURL)
+	cached, meta := readCache(dataPath, metaPath)
+
+	data, fresh, err := l.download(rawURL, meta)
+	switch {
+	case errors.Is(err, context.Canceled):
+		// 用户中断时不回退到缓存
+		return nil, fmt.Errorf("获取远程配置失败: %w", err)
+	case err != nil && cached != nil:
+		// 同一进程中会多次加载配置（各命令、持续监控的每一轮），只提示一次
+		if _, warned := offlineWarned.LoadOrStore(rawURL, true); !warned {
+			fmt.Fprintf(os.Stderr, "⚠️  获取远程配置失败，使用 %s 的缓存: %v\n", meta.FetchedAt.Local().Format("2006-01-02 15:04:05"), err)
+		}
+		return cached, nil
+	case err != nil:
+		return nil, fmt.Errorf("获取远程配置失败: %v", err)
+	case data == nil:
+		// 304 Not Modified
+		return cached, nil
+	}
+
+	// 校验不通过的内容不写入缓存，避免覆盖可用的旧版本
+	if err := l.verifySHA256(data); err != nil {
+		return nil, err
+	}
+	if err := writeCache(dataPath, metaPath, data, fresh); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  缓存远程配置失败: %v\n", err)
+	}
+	return data, nil
+}
+
+// checkPlainHTTP 明文 http 获取的配置可能被篡改，只允许本机地址或设置了 SHA-256 校验时使用
+func (l *Loader) checkPlainHTTP(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(u.Scheme, "http") || l.sha256 != "" || isLoopback(u.Hostname()) {
+		return nil
+	}
+	return fmt.Errorf("远程配置 %s 使用明文 http，内容可能被篡改: 请改用 https、本机地址，或通过 -config-sha256 校验内容", rawURL)
+}
+
+// isLoopback 主机名是否为本机地址
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// download 下载远程配置，meta 不为空时发送条件请求；内容未修改时 data 为 nil
+func (l *Loader) download(rawURL string, meta *remoteMeta) ([]byte, *remoteMeta, error) {
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta != nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	client := l.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && meta != nil {
+		return nil, meta, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("服务器返回错误: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfig+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) > maxRemoteConfig {
+		return nil, nil, fmt.Errorf("远程配置超过 %d MB", maxRemoteConfig>>20)
+	}
+
+	return data, &remoteMeta{
+		URL:          rawURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}, nil
+}
+
+// cachePaths 返回 URL 对应的缓存文件和元数据文件路径（文件名取 URL 的 SHA-256）
+func (l *Loader) cachePaths(rawURL string) (string, string) {
+	dir := l.cacheDir
+	if dir == "" {
+		dir = DefaultCacheDir()
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	base := filepath.Join(dir, hex.EncodeToString(sum[:8]))
+	return base + ".data", base + ".meta.json"
+}
+
+// readCache 读取缓存，缓存不存在或已损坏时返回 nil
+func readCache(dataPath, metaPath string) ([]byte, *remoteMeta) {
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil
+	}
+	meta := &remoteMeta{}
+	if err := json.Unmarshal(raw, meta); err != nil {
+		return nil, nil
+	}
+	return data, meta
+}
+
+// writeCache 写入缓存（先写临时文件再重命名，避免留下不完整的文件）
+func writeCache(dataPath, metaPath string, data []byte, meta *remoteMeta) error {
+	if err := os.MkdirAll(filepath.Dir(dataPath), 0o700); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(dataPath, data); err != nil {
+		return err
+	}
+	return writeFileAtomic(metaPath, raw)
+}
+
+// writeFileAtomic 通过临时文件和重命名写入文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
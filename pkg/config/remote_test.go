@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestLoader_Remote 测试远程配置的下载、ETag 重新验证和离线回退
+func TestLoader_Remote(t *testing.T) {
+	const content = `[{"Name": "A", "URL": "https://a.com"}, {"Name": "B", "URL": "https://b.com"}]`
+	const etag = `"v1"`
+
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(content))
+	}))
+	configURL := srv.URL + "/sites.json"
+
+	cacheDir := t.TempDir()
+	load := func(opts ...Option) (*Config, error) {
+		opts = append([]Option{WithHTTPClient(srv.Client()), WithCacheDir(cacheDir)}, opts...)
+		return NewLoader(opts...).Load(configURL)
+	}
+
+	// 首次下载并写入缓存
+	cfg, err := load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Sites) != 2 {
+		t.Errorf("len(Sites) = %d, want 2", len(cfg.Sites))
+	}
+
+	// 再次加载时带上 ETag，服务器返回 304，使用缓存
+	if cfg, err = load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if notModified.Load() != 1 || len(cfg.Sites) != 2 {
+		t.Errorf("notModified = %d, len(Sites) = %d, want 1, 2", notModified.Load(), len(cfg.Sites))
+	}
+
+	// SHA-256 校验
+	sum := sha256.Sum256([]byte(content))
+	if _, err := load(WithSHA256("sha256:" + hex.EncodeToString(sum[:]))); err != nil {
+		t.Errorf("Load() with matching SHA-256 error = %v", err)
+	}
+	if _, err := load(WithSHA256(strings.Repeat("0", 64))); err == nil || !strings.Contains(err.Error(), "SHA-256") {
+		t.Errorf("Load() with wrong SHA-256 error = %v, want SHA-256 mismatch", err)
+	}
+
+	// 服务器不可用时回退到缓存
+	srv.Close()
+	if cfg, err = load(); err != nil {
+		t.Fatalf("Load() offline error = %v", err)
+	}
+	if len(cfg.Sites) != 2 {
+		t.Errorf("offline len(Sites) = %d, want 2", len(cfg.Sites))
+	}
+
+	// 没有缓存时返回错误
+	if _, err := NewLoader(WithHTTPClient(srv.Client()), WithCacheDir(t.TempDir())).Load(configURL); err == nil {
+		t.Error("Load() offline without cache error = nil, want error")
+	}
+}
+
+// TestLoader_RemoteCanceled 测试取消上下文时中断下载，且不回退到缓存
+func TestLoader_RemoteCanceled(t *testing.T) {
+	const content = `[{"Name": "A", "URL": "https://a.com"}]`
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			// 重新验证时模拟卡住的服务器，直到请求被取消
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	loader := NewLoader(WithHTTPClient(srv.Client()), WithCacheDir(t.TempDir()), WithContext(ctx))
+	if _, err := loader.Load(srv.URL); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, err := loader.Load(srv.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() canceled error = %v, want context.Canceled", err)
+	}
+}
+
+// TestLoader_RemotePlainHTTP 测试明文 http 只允许本机地址或设置了 SHA-256 校验时使用
+func TestLoader_RemotePlainHTTP(t *testing.T) {
+	configURL := "http://config.example.invalid/sites.json"
+	_, err := NewLoader(WithCacheDir(t.TempDir())).Load(configURL)
+	if err == nil || !strings.Contains(err.Error(), "明文 http") {
+		t.Errorf("Load() plain http error = %v, want rejection", err)
+	}
+
+	// 设置校验后允许下载（这里因主机不存在而失败，但不是因为明文 http 被拒绝）
+	_, err = NewLoader(WithCacheDir(t.TempDir()), WithSHA256(strings.Repeat("0", 64))).Load(configURL)
+	if err == nil || strings.Contains(err.Error(), "明文 http") {
+		t.Errorf("Load() plain http with SHA-256 error = %v, want download error", err)
+	}
+}
+
+// TestLoader_RemoteLocalRefs 测试远程配置不能读取本地文件和环境变量，本地配置不受限制
+func TestLoader_RemoteLocalRefs(t *testing.T) {
+	const content = `[
+  {"Name": "A", "URL": "https://a.com", "BodyFile": "/home/user/.ssh/id_rsa"},
+  {"Name": "B", "URL": "https://b.com", "Headers": {"X-Token": "${AWS_SECRET_ACCESS_KEY}", "X-Price": "$5"}},
+  {"Name": "C", "URL": "https://c.com", "Auth": {"Type": "bearer", "TokenEnv": "GITHUB_TOKEN"}}
+]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	_, err := NewLoader(WithHTTPClient(srv.Client()), WithCacheDir(t.TempDir())).Load(srv.URL + "/sites.json")
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Problems) != 3 {
+		t.Fatalf("Load() error = %v, want 3 problems", err)
+	}
+	for i, path := range []string{"Sites[0].BodyFile", "Sites[1].Headers.X-Token", "Sites[2].Auth.TokenEnv"} {
+		if verr.Problems[i].Path != path {
+			t.Errorf("Problems[%d].Path = %s, want %s", i, verr.Problems[i].Path, path)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "sites.json")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLoader().Load(file); err != nil {
+		t.Errorf("Load() local error = %v, want nil", err)
+	}
+}
+
+// TestLoader_RemoteTampered 测试 SHA-256 不一致的新内容不会覆盖缓存
+func TestLoader_RemoteTampered(t *testing.T) {
+	good := `[{"Name": "A", "URL": "https://a.com"}]`
+	var body atomic.Value
+	body.Store(good)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer srv.Close()
+
+	sum := sha256.Sum256([]byte(good))
+	loader := NewLoader(WithHTTPClient(srv.Client()), WithCacheDir(t.TempDir()), WithSHA256(hex.EncodeToString(sum[:])))
+	if _, err := loader.Load(srv.URL); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	body.Store(`[{"Name": "Evil", "URL": "https://evil.example"}]`)
+	if _, err := loader.Load(srv.URL); err == nil {
+		t.Fatal("Load() tampered error = nil, want SHA-256 mismatch")
+	}
+
+	cached, _ := readCache(loader.cachePaths(srv.URL))
+	if string(cached) != good {
+		t.Errorf("cache = %s, want original content", cached)
+	}
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// nodeKind 配置节点类型
+type nodeKind int
+
+const (
+	kindNull nodeKind = iota
+	kindScalar
+	kindObject
+	kindArray
+)
+
+// node 带位置信息的配置节点，用于在校验时报告行列号
+// TOML 解析器不提供键的位置，此时 line 为 0
+type node struct {
+	kind      nodeKind
+	line, col int
+	value     any      // 标量值
+	entries   []*entry // 对象的键值（保持文件中的顺序）
+	items     []*node  // 数组元素
+}
+
+// entry 对象中的一个键
+type entry struct {
+	name      string
+	line, col int
+	value     *node
+}
+
+// get 按名称查找对象中的字段（不区分大小写，与 encoding/json 一致）
+func (n *node) get(name string) *node {
+	if n == nil || n.kind != kindObject {
+		return nil
+	}
+	for _, e := range n.entries {
+		if strings.EqualFold(e.name, name) {
+			return e.value
+		}
+	}
+	return nil
+}
+
+// str 返回字符串标量的值，不是字符串时返回空字符串
+func (n *node) str() string {
+	if n == nil || n.kind != kindScalar {
+		return ""
+	}
+	s, _ := n.value.(string)
+	return s
+}
+
+// find 按字段路径（如 Sites[2].Thresholds）查找节点，找不到时返回 nil
+func (n *node) find(path string) *node {
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name != "" {
+			n = n.get(name)
+		}
+		for rest != "" {
+			idx, tail, _ := strings.Cut(rest, "]")
+			i, err := strconv.Atoi(idx)
+			if n == nil || n.kind != kindArray || err != nil || i < 0 || i >= len(n.items) {
+				return nil
+			}
+			n = n.items[i]
+			rest = strings.TrimPrefix(tail, "[")
+		}
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// parseTree 按格式解析配置文件为节点树，语法错误时返回带位置的问题
+func parseTree(data []byte, format string) (*node, *Problem) {
+	switch format {
+	case FormatYAML:
+		return parseYAMLTree(data)
+	case FormatTOML:
+		return parseTOMLTree(data)
+	default:
+		return parseJSONTree(data)
+	}
+}
+
+// lineIndex 将字节偏移转换为行列号
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	starts := lineIndex{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// position 返回偏移量对应的行号和列号（均从 1 开始）
+func (idx lineIndex) position(offset int) (int, int) {
+	line := sort.Search(len(idx), func(i int) bool { return idx[i] > offset })
+	return line, offset - idx[line-1] + 1
+}
+
+// jsonParser 逐个读取 JSON token 构建节点树
+type jsonParser struct {
+	dec   *json.Decoder
+	data  []byte
+	index lineIndex
+}
+
+// parseJSONTree 解析 JSON 配置
+func parseJSONTree(data []byte) (*node, *Problem) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	p := &jsonParser{dec: dec, data: data, index: newLineIndex(data)}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, &Problem{Message: "配置文件为空"}
+	}
+
+	root, err := p.value()
+	if err == nil {
+		if _, extra := dec.Token(); extra != io.EOF {
+			err = fmt.Errorf("顶层值之后有多余的内容")
+			if extra != nil {
+				err = extra
+			}
+		}
+	}
+	if err != nil {
+		return nil, p.problem(err)
+	}
+	return root, nil
+}
+
+// next 返回下一个 token 的起始偏移（跳过空白和分隔符）
+func (p *jsonParser) next() int {
+	off := int(p.dec.InputOffset())
+	for off < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[off]) >= 0 {
+		off++
+	}
+	return off
+}
+
+// value 读取一个完整的值
+func (p *jsonParser) value() (*node, error) {
+	n := &node{}
+	n.line, n.col = p.index.position(p.next())
+
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		n.kind = kindObject
+		for p.dec.More() {
+			e := &entry{}
+			e.line, e.col = p.index.position(p.next())
+			key, err := p.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			e.name, _ = key.(string)
+			if e.value, err = p.value(); err != nil {
+				return nil, err
+			}
+			n.entries = append(n.entries, e)
+		}
+	case json.Delim('['):
+		n.kind = kindArray
+		for p.dec.More() {
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case nil:
+		n.kind = kindNull
+		return n, nil
+	default:
+		n.kind = kindScalar
+		n.value = tok
+		return n, nil
+	}
+
+	// 读取结束的 } 或 ]
+	if _, err := p.dec.Token(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// problem 将解析错误转换为带位置的问题
+func (p *jsonParser) problem(err error) *Problem {
+	offset := int(p.dec.InputOffset())
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = int(syntaxErr.Offset)
+		if offset > 0 {
+			offset-- // Offset 指向出错字符之后
+		}
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		offset = len(p.data)
+		err = errors.New("文件意外结束")
+	}
+
+	line, col := p.index.position(offset)
+	return &Problem{Line: line, Column: col, Message: fmt.Sprintf("语法错误: %v", err)}
+}
+
+// parseYAMLTree 解析 YAML 配置
+func parseYAMLTree(data []byte) (*node, *Problem) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlProblem(err)
+	}
+	if len(doc.Content) == 0 {
+		return &node{kind: kindObject, line: 1, col: 1}, nil
+	}
+	return fromYAML(doc.Content[0]), nil
+}
+
+// yamlLine 匹配 yaml 错误信息中的行号
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// yamlProblem 将 yaml 错误转换为问题，提取其中的行号
+func yamlProblem(err error) *Problem {
+	msg := err.Error()
+	problem := &Problem{}
+	if m := yamlLine.FindStringSubmatch(msg); m != nil {
+		problem.Line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+	problem.Message = "语法错误: " + strings.TrimPrefix(msg, "yaml: ")
+	return problem
+}
+
+// fromYAML 将 yaml 节点转换为配置节点
+func fromYAML(y *yaml.Node) *node {
+	if y.Kind == yaml.AliasNode && y.Alias != nil {
+		return fromYAML(y.Alias)
+	}
+
+	n := &node{line: y.Line, col: y.Column}
+	switch y.Kind {
+	case yaml.MappingNode:
+		n.kind = kindObject
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			key := y.Content[i]
+			n.entries = append(n.entries, &entry{
+				name:  key.Value,
+				line:  key.Line,
+				col:   key.Column,
+				value: fromYAML(y.Content[i+1]),
+			})
+		}
+	case yaml.SequenceNode:
+		n.kind = kindArray
+		for _, item := range y.Content {
+			n.items = append(n.items, fromYAML(item))
+		}
+	case yaml.ScalarNode:
+		if y.Tag == "!!null" {
+			n.kind = kindNull
+			break
+		}
+		n.kind = kindScalar
+		_ = y.Decode(&n.value)
+	}
+	return n
+}
+
+// parseTOMLTree 解析 TOML 配置（只有语法错误带位置）
+func parseTOMLTree(data []byte) (*node, *Problem) {
+	table := map[string]any{}
+	if _, err := toml.Decode(string(data), &table); err != nil {
+		problem := &Problem{Message: "语法错误: " + err.Error()}
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			problem.Line, problem.Column = parseErr.Position.Line, parseErr.Position.Col
+			problem.Message = "语法错误: " + parseErr.Message
+		}
+		return nil, problem
+	}
+	return fromValue(table), nil
+}
+
+// fromValue 将解码后的值转换为配置节点（对象的键按字母顺序排列）
+func fromValue(v any) *node {
+	switch v := v.(type) {
+	case nil:
+		return &node{kind: kindNull}
+	case map[string]any:
+		n := &node{kind: kindObject}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			n.entries = append(n.entries, &entry{name: k, value: fromValue(v[k])})
+		}
+		return n
+	case []map[string]any:
+		n := &node{kind: kindArray}
+		for _, item := range v {
+			n.items = append(n.items, fromValue(item))
+		}
+		return n
+	case []any:
+		n := &node{kind: kindArray}
+		for _, item := range v {
+			n.items = append(n.items, fromValue(item))
+		}
+		return n
+	default:
+		return &node{kind: kindScalar, value: v}
+	}
+}
@@ -0,0 +1,359 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// Problem 配置文件中的一处问题
+type Problem struct {
+	Line    int    // 行号（从 1 开始，0 表示未知）
+	Column  int    // 列号（从 1 开始，0 表示未知）
+	Path    string // 字段路径，如 Sites[2].URL
+	Message string // 问题描述
+}
+
+// String 返回 "行:列: 路径: 描述" 形式的问题描述
+func (p Problem) String() string {
+	var b strings.Builder
+	if p.Line > 0 {
+		fmt.Fprintf(&b, "%d:", p.Line)
+		if p.Column > 0 {
+			fmt.Fprintf(&b, "%d:", p.Column)
+		}
+		b.WriteString(" ")
+	}
+	if p.Path != "" {
+		b.WriteString(p.Path + ": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// ValidationError 配置文件校验失败，包含发现的全部问题
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+// newValidationError 创建校验错误，问题按在文件中的位置排序，位置未知的排在最后
+func newValidationError(file string, problems []Problem) *ValidationError {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Line == 0 || b.Line == 0 {
+			return b.Line == 0 && a.Line != 0
+		}
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return &ValidationError{File: file, Problems: problems}
+}
+
+// Error 每行输出一个问题，格式为 文件:行:列: 路径: 描述
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s 有 %d 处问题:", e.File, len(e.Problems))
+	for _, p := range e.Problems {
+		sep := ":"
+		if p.Line == 0 {
+			sep = ": "
+		}
+		fmt.Fprintf(&b, "\n  %s%s%s", e.File, sep, p)
+	}
+	return b.String()
+}
+
+// validator 在节点树上收集配置问题
+type validator struct {
+	loader   *Loader
+	remote   bool // 配置来自 URL，不能引用本机的文件和环境变量
+	problems []Problem
+}
+
+// add 记录一处问题，位置取自节点
+func (v *validator) add(n *node, path, format string, args ...any) {
+	p := Problem{Path: path, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		p.Line, p.Column = n.line, n.col
+	}
+	v.problems = append(v.problems, p)
+}
+
+// check 校验整个配置：字段结构、全局选项、站点和 IP API 提供商
+func (v *validator) check(root *node) {
+	if root.kind == kindArray {
+		// 旧格式：站点数组
+		v.checkType(root, reflect.TypeOf([]tester.Site(nil)), "Sites")
+		v.checkSites(root, "Sites")
+		return
+	}
+
+	v.checkType(root, reflect.TypeOf(Config{}), "")
+	v.checkOptions(root.get("Options"), "Options")
+	v.checkSites(root.get("Sites"), "Sites")
+	v.checkProviders(root.get("Providers"))
+
+	if profiles := root.get("Profiles"); profiles != nil && profiles.kind == kindObject {
+		for _, e := range profiles.entries {
+			path := joinPath("Profiles", e.name)
+			v.checkOptions(e.value.get("Options"), joinPath(path, "Options"))
+			v.checkSites(e.value.get("Sites"), joinPath(path, "Sites"))
+		}
+	}
+}
+
+// checkType 按 Go 类型（与 encoding/json 的字段匹配规则一致）检查节点结构，拒绝未知字段
+func (v *validator) checkType(n *node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if n.kind == kindNull {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.kind != kindObject {
+			v.add(n, path, "应为对象")
+			return
+		}
+		for _, e := range n.entries {
+			field, ok := jsonField(t, e.name)
+			if !ok {
+				v.problems = append(v.problems, Problem{
+					Line: e.line, Column: e.col, Path: joinPath(path, e.name),
+					Message: fmt.Sprintf("未知字段 %q", e.name),
+				})
+				continue
+			}
+			v.checkType(e.value, field.Type, joinPath(path, jsonName(field)))
+		}
+	case reflect.Slice:
+		if n.kind != kindArray {
+			v.add(n, path, "应为数组")
+			return
+		}
+		for i, item := range n.items {
+			v.checkType(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		if n.kind != kindObject {
+			v.add(n, path, "应为对象")
+			return
+		}
+		for _, e := range n.entries {
+			v.checkType(e.value, t.Elem(), joinPath(path, e.name))
+		}
+	case reflect.Interface:
+		// 任意值
+	case reflect.String:
+		if _, ok := n.value.(string); !ok || n.kind != kindScalar {
+			v.add(n, path, "应为字符串")
+		}
+	case reflect.Bool:
+		if _, ok := n.value.(bool); !ok || n.kind != kindScalar {
+			v.add(n, path, "应为布尔值")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f, ok := number(n); !ok || f != math.Trunc(f) {
+			v.add(n, path, "应为整数")
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := number(n); !ok {
+			v.add(n, path, "应为数字")
+		}
+	}
+}
+
+// checkOptions 检查全局选项是否为已知的 flag（需通过 WithFlags 提供 flag 集合）
+func (v *validator) checkOptions(options *node, path string) {
+	if options == nil || options.kind != kindObject || v.loader.flags == nil {
+		return
+	}
+	for _, e := range options.entries {
+		if v.loader.flags.Lookup(e.name) == nil || nonFileFlags[e.name] {
+			v.problems = append(v.problems, Problem{
+				Line: e.line, Column: e.col, Path: joinPath(path, e.name),
+				Message: fmt.Sprintf("未知的配置项 %q", e.name),
+			})
+		}
+	}
+}
+
+// checkSites 检查站点的名称、URL，以及名称和 URL 是否重复
+func (v *validator) checkSites(sites *node, prefix string) {
+	if sites == nil || sites.kind != kindArray {
+		return
+	}
+	if len(sites.items) == 0 {
+		v.add(sites, prefix, "没有站点")
+		return
+	}
+
+	names := make(map[string]string)
+	targets := make(map[string]string)
+	for i, site := range sites.items {
+		path := fmt.Sprintf("%s[%d]", prefix, i)
+		if site.kind != kindObject {
+			continue
+		}
+
+		nameNode := site.get("Name")
+		if name := strings.TrimSpace(nameNode.str()); name == "" {
+			v.add(site, path, "缺少 Name")
+		} else if prev, ok := names[strings.ToLower(name)]; ok {
+			v.add(nameNode, path+".Name", "名称 %q 与 %s 重复", name, prev)
+		} else {
+			names[strings.ToLower(name)] = path
+		}
+
+		urlNode := site.get("URL")
+		if strings.TrimSpace(urlNode.str()) == "" {
+			v.add(site, path, "缺少 URL")
+		} else if v.checkURL(urlNode, path+".URL", tester.Schemes) {
+			// 同一 URL 可以用不同的方法或路径测试，只有三者都相同时才视为重复
+			key := strings.Join([]string{
+				strings.TrimSpace(urlNode.str()),
+				strings.ToUpper(site.get("Method").str()),
+				site.get("Path").str(),
+			}, " ")
+			if prev, ok := targets[key]; ok {
+				v.add(urlNode, path+".URL", "URL 与 %s 重复", prev)
+			} else {
+				targets[key] = path
+			}
+		}
+
+		for _, field := range []string{"DownloadURL", "UploadURL"} {
+			if n := site.get(field); n.str() != "" {
+				v.checkURL(n, path+"."+field, []string{tester.SchemeHTTP, tester.SchemeHTTPS})
+			}
+		}
+
+		if v.remote {
+			v.checkLocalRefs(site, path)
+		}
+	}
+}
+
+// checkLocalRefs 远程配置不能读取本地文件和环境变量，避免被篡改的配置把本机的文件或密钥发送到任意地址
+func (v *validator) checkLocalRefs(site *node, path string) {
+	if n := site.get("BodyFile"); n.str() != "" {
+		v.add(n, path+".BodyFile", "远程配置不能通过 BodyFile 读取本地文件")
+	}
+	if headers := site.get("Headers"); headers != nil && headers.kind == kindObject {
+		for _, e := range headers.entries {
+			if tester.HasEnvRef(e.value.str()) {
+				v.problems = append(v.problems, Problem{
+					Line: e.line, Column: e.col, Path: joinPath(path+".Headers", e.name),
+					Message: "远程配置的请求头不能引用环境变量",
+				})
+			}
+		}
+	}
+	for _, field := range []string{"PasswordEnv", "TokenEnv"} {
+		if n := site.get("Auth").get(field); n.str() != "" {
+			v.add(n, path+".Auth."+field, "远程配置不能从环境变量读取认证信息")
+		}
+	}
+}
+
+// checkProviders 检查 IP API 提供商
+func (v *validator) checkProviders(providers *node) {
+	if providers == nil || providers.kind != kindArray {
+		return
+	}
+	for i, p := range providers.items {
+		path := fmt.Sprintf("Providers[%d]", i)
+		if p.kind != kindObject {
+			continue
+		}
+		if p.get("Name").str() == "" {
+			v.add(p, path, "缺少 Name")
+		}
+		if n := p.get("URL"); n.str() == "" {
+			v.add(p, path, "缺少 URL")
+		} else {
+			v.checkURL(n, path+".URL", []string{tester.SchemeHTTP, tester.SchemeHTTPS})
+		}
+		if n := p.get("Format"); n != nil && n.str() != "json" && n.str() != "text" {
+			v.add(n, path+".Format", "Format 应为 json 或 text")
+		}
+	}
+}
+
+// checkURL 检查 URL 语法、协议和主机名，通过时返回 true
+func (v *validator) checkURL(n *node, path string, schemes []string) bool {
+	raw := strings.TrimSpace(n.str())
+	u, err := url.Parse(raw)
+	switch {
+	case err != nil:
+		v.add(n, path, "URL 无效: %v", err)
+	case u.Scheme == "":
+		v.add(n, path, "URL 缺少协议（如 https://）: %s", raw)
+	case !containsFold(schemes, u.Scheme):
+		v.add(n, path, "不支持的协议 %s（支持 %s）", u.Scheme, strings.Join(schemes, ", "))
+	case u.Hostname() == "":
+		v.add(n, path, "URL 缺少主机名: %s", raw)
+	default:
+		return true
+	}
+	return false
+}
+
+// jsonField 按 encoding/json 的规则（不区分大小写）查找 JSON 字段名对应的结构体字段
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if strings.EqualFold(jsonName(field), name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// jsonName 返回结构体字段的 JSON 名称
+func jsonName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// number 返回数字标量的值
+func number(n *node) (float64, bool) {
+	if n.kind != kindScalar {
+		return 0, false
+	}
+	switch v := n.value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
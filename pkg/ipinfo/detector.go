@@ -3,11 +3,14 @@ package ipinfo
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/proxy"
 )
 
 // Detector IP 检测器
@@ -16,22 +19,41 @@ type Detector struct {
 	providers []Provider
 }
 
+// Option 检测器配置项
+type Option func(*Detector)
+
+// WithProviders 使用自定义的 API 提供商列表（按顺序故障转移），列表为空时保留默认列表
+func WithProviders(providers []Provider) Option {
+	return func(d *Detector) {
+		if len(providers) > 0 {
+			d.providers = providers
+		}
+	}
+}
+
 // NewDetector 创建新的 IP 检测器
-func NewDetector(client *http.Client) *Detector {
-	return &Detector{
+func NewDetector(client *http.Client, opts ...Option) *Detector {
+	d := &Detector{
 		client:    client,
 		providers: DefaultProviders,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
-// Detect 检测 IP 信息（带故障转移）
-func (d *Detector) Detect() (*IPInfo, error) {
+// Detect 检测 IP 信息（带故障转移），ctx 取消后不再尝试后续提供商
+func (d *Detector) Detect(ctx context.Context) (*IPInfo, error) {
 	var lastErr error
 	for _, provider := range d.providers {
-		ipInfo, err := d.fetchFromProvider(provider)
+		ipInfo, err := d.fetchFromProvider(ctx, provider)
 		if err == nil {
 			return ipInfo, nil
 		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		lastErr = err
 		fmt.Printf("⚠️  %s 失败，尝试下一个...\n", provider.Name)
 	}
@@ -39,12 +61,32 @@ func (d *Detector) Detect() (*IPInfo, error) {
 	return nil, fmt.Errorf("所有 IP API 都失败: %v", lastErr)
 }
 
+// DetectDualStack 分别通过 IPv4 和 IPv6 连接检测出口 IP
+// 单个地址族失败时记录在 IPv4Err/IPv6Err 中，仅在 ctx 取消时返回错误
+func (d *Detector) DetectDualStack(ctx context.Context) (*DualStackInfo, error) {
+	info := &DualStackInfo{}
+
+	v4 := &Detector{client: proxy.ForceFamily(d.client, "tcp4"), providers: d.providers}
+	info.IPv4, info.IPv4Err = v4.Detect(ctx)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	v6 := &Detector{client: proxy.ForceFamily(d.client, "tcp6"), providers: d.providers}
+	info.IPv6, info.IPv6Err = v6.Detect(ctx)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return info, nil
+}
+
 // DetectScore 检测 IP 纯净度（简化版，使用已有的 IP 信息）
-func (d *Detector) DetectScore() (*IPScore, error) {
+func (d *Detector) DetectScore(ctx context.Context) (*IPScore, error) {
 	// 先获取基本 IP 信息
-	info, err := d.Detect()
+	info, err := d.Detect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("获取 IP 信息失败: %v", err)
+		return nil, fmt.Errorf("获取 IP 信息失败: %w", err)
 	}
 
 	score := &IPScore{
@@ -159,8 +201,13 @@ func contains(s, substr string) bool {
 }
 
 // fetchFromProvider 从指定 API 获取 IP 信息
-func (d *Detector) fetchFromProvider(provider Provider) (*IPInfo, error) {
-	resp, err := d.client.Get(provider.URL)
+func (d *Detector) fetchFromProvider(ctx context.Context, provider Provider) (*IPInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", provider.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	resp, err := d.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("请求失败: %v", err)
 	}
@@ -1,6 +1,7 @@
 package ipinfo
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -64,7 +65,7 @@ CHINANET Guangdong province network`,
 			}
 
 			// 执行测试
-			result, err := detector.fetchFromProvider(provider)
+			result, err := detector.fetchFromProvider(context.Background(), provider)
 			if err != nil {
 				t.Fatalf("fetchFromProvider() error = %v", err)
 			}
@@ -130,7 +131,7 @@ func TestDetector_FetchFromProvider_HTTPErrors(t *testing.T) {
 				Format: "json",
 			}
 
-			_, err := detector.fetchFromProvider(provider)
+			_, err := detector.fetchFromProvider(context.Background(), provider)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("fetchFromProvider() error = %v, wantErr %v", err, tt.wantErr)
@@ -158,7 +159,7 @@ func TestDetector_FetchFromProvider_Timeout(t *testing.T) {
 		Format: "json",
 	}
 
-	_, err := detector.fetchFromProvider(provider)
+	_, err := detector.fetchFromProvider(context.Background(), provider)
 
 	if err == nil {
 		t.Error("Expected timeout error, got nil")
@@ -192,7 +193,7 @@ func TestDetector_FetchFromProvider_InvalidURL(t *testing.T) {
 				Format: "json",
 			}
 
-			_, err := detector.fetchFromProvider(provider)
+			_, err := detector.fetchFromProvider(context.Background(), provider)
 
 			if err == nil {
 				t.Error("Expected error for invalid URL, got nil")
@@ -218,7 +219,7 @@ func TestDetector_FetchFromProvider_UnsupportedFormat(t *testing.T) {
 		Format: "xml", // 不支持的格式
 	}
 
-	_, err := detector.fetchFromProvider(provider)
+	_, err := detector.fetchFromProvider(context.Background(), provider)
 
 	if err == nil {
 		t.Error("Expected error for unsupported format, got nil")
@@ -249,7 +250,7 @@ func TestDetector_Detect_Failover(t *testing.T) {
 		},
 	}
 
-	result, err := detector.Detect()
+	result, err := detector.Detect(context.Background())
 
 	if err != nil {
 		t.Fatalf("Detect() should succeed with failover, got error: %v", err)
@@ -280,7 +281,7 @@ func TestDetector_Detect_AllFail(t *testing.T) {
 		},
 	}
 
-	_, err := detector.Detect()
+	_, err := detector.Detect(context.Background())
 
 	if err == nil {
 		t.Error("Expected error when all providers fail, got nil")
@@ -308,7 +309,7 @@ func TestDetector_Detect_FirstSuccess(t *testing.T) {
 		},
 	}
 
-	result, err := detector.Detect()
+	result, err := detector.Detect(context.Background())
 
 	if err != nil {
 		t.Fatalf("Detect() error = %v", err)
@@ -358,10 +359,72 @@ func TestDetector_EmptyResponseBody(t *testing.T) {
 		Format: "json",
 	}
 
-	_, err := detector.fetchFromProvider(provider)
+	_, err := detector.fetchFromProvider(context.Background(), provider)
 
 	// 空 JSON 响应体应该能够解析（虽然字段为空）
 	if err != nil {
 		t.Logf("Empty response body error: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+// TestDetector_Detect_Canceled 测试取消后不再尝试后续 API
+func TestDetector_Detect_Canceled(t *testing.T) {
+	callCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	detector := &Detector{
+		client: client,
+		providers: []Provider{
+			{Name: "first", URL: server.URL, Format: "json"},
+			{Name: "second", URL: server.URL, Format: "json"},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := detector.Detect(ctx)
+
+	if err != context.Canceled {
+		t.Errorf("Detect() error = %v, want %v", err, context.Canceled)
+	}
+
+	if callCount != 0 {
+		t.Errorf("Expected 0 API calls after cancel, got %d", callCount)
+	}
+}
+
+// TestDetector_DetectDualStack 测试分别通过 IPv4 和 IPv6 检测
+func TestDetector_DetectDualStack(t *testing.T) {
+	// 只监听 IPv4，IPv6 检测应失败
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ip": "203.0.113.45", "country": "United States"}`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	detector := &Detector{
+		client: client,
+		providers: []Provider{
+			{Name: "local", URL: server.URL, Format: "json"},
+		},
+	}
+
+	info, err := detector.DetectDualStack(context.Background())
+	if err != nil {
+		t.Fatalf("DetectDualStack() error = %v", err)
+	}
+
+	if info.IPv4Err != nil || info.IPv4 == nil || info.IPv4.IP != "203.0.113.45" {
+		t.Errorf("IPv4 = %+v, IPv4Err = %v", info.IPv4, info.IPv4Err)
+	}
+	if info.IPv6Err == nil {
+		t.Errorf("IPv6Err = nil, want error for IPv4-only server")
+	}
+}
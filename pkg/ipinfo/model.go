@@ -12,6 +12,14 @@ type IPInfo struct {
 	Org         string `json:"org"`
 }
 
+// DualStackInfo 分别通过 IPv4 和 IPv6 检测到的出口信息
+type DualStackInfo struct {
+	IPv4    *IPInfo
+	IPv6    *IPInfo
+	IPv4Err error // IPv4 检测失败的原因
+	IPv6Err error // IPv6 检测失败的原因（如本地没有 IPv6 出口）
+}
+
 // Provider IP API 提供商
 type Provider struct {
 	Name   string
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// PrintBufferbloatTable 以表格形式输出负载下延迟测试结果
+func PrintBufferbloatTable(results []tester.TestResult) {
+	// 表头
+	fmt.Println("┌─────────────────┬──────────┬──────────────────┬──────────────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-8s │ %-16s │ %-16s │ %-8s │\n", "目标", "空闲", "下载时", "上传时", "等级")
+	fmt.Println("├─────────────────┼──────────┼──────────────────┼──────────────────┼──────────┤")
+
+	// 数据行
+	for _, result := range results {
+		bb := result.Bufferbloat
+		if !result.Success || bb == nil {
+			fmt.Printf("│ ✗ %-13s │ %8s │ %16s │ %16s │ ✗ %-6s │\n",
+				result.Name, failureCode(result), "-", "-", result.Status)
+			continue
+		}
+
+		statusIcon := "✓"
+		switch bb.Grade {
+		case "C", "D":
+			statusIcon = "⚠"
+		case "F":
+			statusIcon = "✗"
+		}
+
+		fmt.Printf("│ %s %-13s │ %8s │ %16s │ %16s │ %s %-6s │\n",
+			statusIcon,
+			result.Name,
+			formatPhase(bb.Idle.Median),
+			formatLoadPhase(bb.Download),
+			formatLoadPhase(bb.Upload),
+			statusIcon,
+			bb.Grade,
+		)
+	}
+
+	fmt.Println("└─────────────────┴──────────┴──────────────────┴──────────────────┴──────────┘")
+
+	for _, result := range results {
+		bb := result.Bufferbloat
+		if bb == nil {
+			continue
+		}
+
+		var notes []string
+		for _, phase := range []struct {
+			label string
+			phase *tester.LoadPhase
+		}{{"下载", bb.Download}, {"上传", bb.Upload}} {
+			switch {
+			case phase.phase == nil:
+			case phase.phase.Error != "":
+				notes = append(notes, fmt.Sprintf("%s负载失败: %s", phase.label, phase.phase.Error))
+			default:
+				notes = append(notes, fmt.Sprintf("%s %.1f Mbps", phase.label, phase.phase.Mbps))
+			}
+		}
+		fmt.Printf("  %s: 附加延迟 %d ms, %s\n", result.Name, bb.Added.Milliseconds(), strings.Join(notes, ", "))
+	}
+}
+
+// formatLoadPhase 格式化负载阶段的延迟中位数和附加延迟，如 "180 ms (+150)"
+func formatLoadPhase(phase *tester.LoadPhase) string {
+	switch {
+	case phase == nil:
+		return "-"
+	case phase.Error != "":
+		return "负载失败"
+	case phase.Latency.Median == 0:
+		// 负载期间所有探测都超时
+		return "超时"
+	default:
+		return fmt.Sprintf("%s (+%d)", formatPhase(phase.Latency.Median), phase.Added.Milliseconds())
+	}
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// PrintCertReport 输出 HTTPS/TLS 站点的证书报告
+func PrintCertReport(results []tester.TestResult) {
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("🔒 证书报告")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	count := 0
+	for _, result := range results {
+		cert := result.Cert
+		if cert == nil {
+			continue
+		}
+		if count > 0 {
+			fmt.Println()
+		}
+		count++
+
+		statusIcon := "✓"
+		if result.Degraded {
+			statusIcon = "⚠"
+		}
+
+		protocol := cert.Version + " / " + cert.CipherSuite
+		if cert.ALPN != "" {
+			protocol += " / " + cert.ALPN
+		}
+
+		fmt.Printf("%s %s\n", statusIcon, result.Name)
+		fmt.Printf("  协议:     %s\n", protocol)
+		fmt.Printf("  主体:     %s\n", cert.Subject)
+		fmt.Printf("  签发者:   %s\n", cert.Issuer)
+		fmt.Printf("  域名:     %s\n", strings.Join(cert.SANs, ", "))
+		fmt.Printf("  证书链:   %s\n", strings.Join(cert.Chain, " → "))
+		if cert.DaysLeft < 0 {
+			fmt.Printf("  有效期至: %s (已过期 %d 天)\n", cert.NotAfter.Format("2006-01-02"), -cert.DaysLeft)
+		} else {
+			fmt.Printf("  有效期至: %s (剩余 %d 天)\n", cert.NotAfter.Format("2006-01-02"), cert.DaysLeft)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Printf("  ⚠ %s\n", warning)
+		}
+	}
+
+	if count == 0 {
+		fmt.Println("没有 HTTPS/TLS 站点的证书信息")
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// PrintFamilyTable 以表格形式对比 IPv4 与 IPv6 的探测结果
+func PrintFamilyTable(results []tester.TestResult) {
+	// 表头
+	fmt.Println("┌─────────────────┬────────────┬────────────┬─────────────────┬───────────────────────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-10s │ %-10s │ %-15s │ %-25s │ %-8s │\n", "网站", "IPv4", "IPv6", "IPv4 地址", "IPv6 地址", "结论")
+	fmt.Println("├─────────────────┼────────────┼────────────┼─────────────────┼───────────────────────────┼──────────┤")
+
+	// 数据行
+	for _, result := range results {
+		v4, v6 := result.IPv4, result.IPv6
+		fmt.Printf("│ %-15s │ %10s │ %10s │ %-15s │ %-25s │ %-8s │\n",
+			result.Name,
+			formatFamilyLatency(v4),
+			formatFamilyLatency(v6),
+			truncate(familyAddr(v4), 15),
+			truncate(familyAddr(v6), 25),
+			familyVerdict(v4, v6),
+		)
+	}
+
+	fmt.Println("└─────────────────┴────────────┴────────────┴─────────────────┴───────────────────────────┴──────────┘")
+}
+
+// formatFamilyLatency 格式化单个地址族的延迟，失败时显示失败分类
+func formatFamilyLatency(r *tester.FamilyResult) string {
+	switch {
+	case r == nil:
+		return "-"
+	case r.Success:
+		return fmt.Sprintf("%d ms", r.Latency.Milliseconds())
+	case r.Category != tester.ErrorNone:
+		return string(r.Category)
+	default:
+		return r.Status
+	}
+}
+
+// familyAddr 返回地址族结果中的服务器地址
+func familyAddr(r *tester.FamilyResult) string {
+	if r == nil || r.Addr == "" {
+		return "-"
+	}
+	return r.Addr
+}
+
+// familyVerdict 根据两个地址族的结果给出结论
+func familyVerdict(v4, v6 *tester.FamilyResult) string {
+	if v4 == nil || v6 == nil {
+		return "-"
+	}
+	switch {
+	case v4.Success && v6.Success:
+		return "双栈正常"
+	case v4.Success && !hasFamilyAddress(v6):
+		return "仅IPv4"
+	case v4.Success:
+		return "IPv6故障"
+	case v6.Success && !hasFamilyAddress(v4):
+		return "仅IPv6"
+	case v6.Success:
+		return "IPv4故障"
+	default:
+		return "不可用"
+	}
+}
+
+// hasFamilyAddress 站点是否有该地址族的地址（DNS 类失败说明没有对应记录）
+func hasFamilyAddress(r *tester.FamilyResult) bool {
+	return r.Success || r.Category != tester.ErrorDNS
+}
+
+// ipv6Health 根据分地址族的结果判断 IPv6 是否健康，没有分地址族结果时返回空字符串
+// 只统计有 IPv6 地址的站点
+func ipv6Health(results []tester.TestResult) string {
+	var capable, ok int
+	var failed []string
+	for _, result := range results {
+		if result.IPv6 == nil || !hasFamilyAddress(result.IPv6) {
+			continue
+		}
+		capable++
+		if result.IPv6.Success {
+			ok++
+		} else {
+			failed = append(failed, result.Name)
+		}
+	}
+
+	if capable == 0 {
+		for _, result := range results {
+			if result.IPv6 != nil {
+				return "无法判断（站点均没有 IPv6 地址）"
+			}
+		}
+		return ""
+	}
+
+	switch ok {
+	case capable:
+		return fmt.Sprintf("健康 (%d/%d)", ok, capable)
+	case 0:
+		return fmt.Sprintf("不可用 (0/%d)", capable)
+	default:
+		return fmt.Sprintf("不稳定 (%d/%d，失败: %s)", ok, capable, strings.Join(failed, ", "))
+	}
+}
+
+// truncate 截断过长的字符串
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n-3] + "..."
+	}
+	return s
+}
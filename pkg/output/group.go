@@ -0,0 +1,71 @@
+package output
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// groupStats 单个分组的统计
+type groupStats struct {
+	results []tester.TestResult
+	online  int
+	latency time.Duration
+}
+
+// PrintGroupTable 按分组或标签汇总可用率和平均延迟，results 与 sites 按下标一一对应
+// by 为 tester.GroupByGroup 或 tester.GroupByTag
+func PrintGroupTable(sites []tester.Site, results []tester.TestResult, by string) {
+	var order []string
+	groups := make(map[string]*groupStats)
+	for i, result := range results {
+		for _, key := range sites[i].GroupKeys(by) {
+			g, ok := groups[key]
+			if !ok {
+				g = &groupStats{}
+				groups[key] = g
+				order = append(order, key)
+			}
+			g.results = append(g.results, result)
+			if result.Success {
+				g.online++
+				g.latency += result.Latency
+			}
+		}
+	}
+
+	// 表头
+	fmt.Println("┌─────────────────┬──────────┬──────────┬──────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-8s │ %-8s │ %-8s │ %-8s │\n", "分组", "在线", "可用率", "平均延迟", "评级")
+	fmt.Println("├─────────────────┼──────────┼──────────┼──────────┼──────────┤")
+
+	// 数据行
+	for _, key := range order {
+		g := groups[key]
+		avg, level := "-", "不可用"
+		if g.online > 0 {
+			avg = formatPhase(g.latency / time.Duration(g.online))
+			level = tester.OverallLevel(g.results).String()
+		}
+
+		statusIcon := "✓"
+		switch {
+		case g.online == 0:
+			statusIcon = "✗"
+		case g.online < len(g.results):
+			statusIcon = "⚠"
+		}
+
+		fmt.Printf("│ %s %-13s │ %8s │ %7.1f%% │ %8s │ %-8s │\n",
+			statusIcon,
+			key,
+			fmt.Sprintf("%d/%d", g.online, len(g.results)),
+			float64(g.online)/float64(len(g.results))*100,
+			avg,
+			level,
+		)
+	}
+
+	fmt.Println("└─────────────────┴──────────┴──────────┴──────────┴──────────┘")
+}
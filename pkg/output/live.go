@@ -0,0 +1,150 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// spinnerFrames 等待动画的帧
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// LiveTable 逐行输出测试结果的表格：站点完成即输出一行，
+// 终端中在表格下方用旋转动画显示尚未完成的站点
+type LiveTable struct {
+	mu       sync.Mutex
+	detailed bool
+	live     bool // 输出是否为终端（非终端时不显示动画）
+	names    []string
+	pending  map[int]bool
+	frame    int
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewLiveTable 输出表头并开始显示等待动画，detailed 为 true 时输出各阶段耗时
+func NewLiveTable(sites []tester.Site, detailed bool) *LiveTable {
+	l := &LiveTable{
+		detailed: detailed,
+		live:     isTerminal(os.Stdout),
+		names:    make([]string, len(sites)),
+		pending:  make(map[int]bool, len(sites)),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for i, site := range sites {
+		l.names[i] = site.Name
+		l.pending[i] = true
+	}
+
+	if detailed {
+		printDetailedHeader()
+	} else {
+		printTableHeader()
+	}
+
+	if !l.live {
+		close(l.done)
+		return l
+	}
+
+	l.drawSpinner()
+	go l.animate()
+	return l
+}
+
+// Add 输出一个已完成站点的结果行，idx 为站点在 sites 中的下标
+func (l *LiveTable) Add(idx int, result tester.TestResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.pending, idx)
+	l.clearSpinner()
+	if l.detailed {
+		printDetailedRow(result)
+	} else {
+		printTableRow(result)
+	}
+	l.drawSpinner()
+}
+
+// Close 停止等待动画，输出表尾和表格下方的说明
+func (l *LiveTable) Close(results []tester.TestResult) {
+	if l.live {
+		close(l.stop)
+	}
+	<-l.done
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clearSpinner()
+	l.live = false
+
+	if l.detailed {
+		printDetailedFooter()
+		printNotes(results)
+		printRedirects(results)
+	} else {
+		printTableFooter()
+		printNotes(results)
+	}
+}
+
+// animate 定期刷新等待动画，直到 Close
+func (l *LiveTable) animate() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			l.frame++
+			l.clearSpinner()
+			l.drawSpinner()
+			l.mu.Unlock()
+		}
+	}
+}
+
+// drawSpinner 在当前行输出等待动画和尚未完成的站点（不换行，由下一次输出覆盖）
+func (l *LiveTable) drawSpinner() {
+	if !l.live || len(l.pending) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(l.pending))
+	for i, name := range l.names {
+		if l.pending[i] {
+			names = append(names, name)
+		}
+	}
+	list := []rune(strings.Join(names, ", "))
+	if len(list) > 50 {
+		list = append(list[:47], []rune("...")...)
+	}
+	fmt.Printf("%s 等待 %d 个站点: %s", spinnerFrames[l.frame%len(spinnerFrames)], len(names), string(list))
+}
+
+// clearSpinner 清除当前行的等待动画
+func (l *LiveTable) clearSpinner() {
+	if l.live {
+		fmt.Print("\r\033[K")
+	}
+}
+
+// isTerminal 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// PrintPageLoadTable 以表格形式输出页面加载测试结果，并列出加载失败的子资源
+func PrintPageLoadTable(results []tester.TestResult) {
+	// 表头
+	fmt.Println("┌─────────────────┬──────────┬──────────┬──────────┬──────┬────────────────────────────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-8s │ %-8s │ %-8s │ %-4s │ %-30s │ %-8s │\n", "网站", "文档", "总耗时", "资源", "主机", "最慢资源", "状态")
+	fmt.Println("├─────────────────┼──────────┼──────────┼──────────┼──────┼────────────────────────────────┼──────────┤")
+
+	// 数据行
+	for _, result := range results {
+		page := result.PageLoad
+		if !result.Success || page == nil {
+			fmt.Printf("│ ✗ %-13s │ %8s │ %8s │ %8s │ %4s │ %-30s │ ✗ %-6s │\n",
+				result.Name, failureCode(result), "-", "-", "-", "-", result.Status)
+			continue
+		}
+
+		statusIcon := "✓"
+		if result.Degraded || result.Level == tester.LevelFair || result.Level == tester.LevelPoor {
+			statusIcon = "⚠"
+		}
+
+		failed := 0
+		for _, r := range page.Resources {
+			if r.Failed() {
+				failed++
+			}
+		}
+
+		slowest := "-"
+		if page.Slowest != nil {
+			slowest = truncate(fmt.Sprintf("%d ms %s", page.Slowest.Latency.Milliseconds(), page.Slowest.Host), 30)
+		}
+
+		fmt.Printf("│ %s %-13s │ %8s │ %8s │ %8s │ %4d │ %-30s │ %s %-6s │\n",
+			statusIcon,
+			result.Name,
+			formatPhase(page.Document),
+			formatPhase(page.Total),
+			fmt.Sprintf("%d/%d", len(page.Resources)-failed, len(page.Resources)),
+			page.Hosts,
+			slowest,
+			statusIcon,
+			result.Status,
+		)
+	}
+
+	fmt.Println("└─────────────────┴──────────┴──────────┴──────────┴──────┴────────────────────────────────┴──────────┘")
+
+	for _, result := range results {
+		if result.PageLoad == nil {
+			continue
+		}
+		for _, r := range result.PageLoad.Resources {
+			if !r.Failed() {
+				continue
+			}
+			party := "同源"
+			if r.ThirdParty {
+				party = "第三方"
+			}
+			code := string(r.Category)
+			if r.Category == tester.ErrorNone {
+				code = "canceled"
+			}
+			fmt.Printf("  ✗ %s: [%s %s] %s (%s)\n", result.Name, party, r.Kind, r.URL, code)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/tester"
+)
+
+// Session 持续监控期间的累计统计
+type Session struct {
+	start  time.Time
+	cycles int
+	order  []string
+	sites  map[string]*siteSession
+}
+
+// siteSession 单个站点在整个监控期间的统计
+type siteSession struct {
+	total        int
+	online       int
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	retries      int // 累计重试次数，反映站点是否不稳定
+}
+
+// NewSession 创建监控会话统计
+func NewSession() *Session {
+	return &Session{
+		start: time.Now(),
+		sites: make(map[string]*siteSession),
+	}
+}
+
+// Record 记录一轮完整的测试结果
+func (s *Session) Record(results []tester.TestResult) {
+	s.cycles++
+	for _, result := range results {
+		site, ok := s.sites[result.Name]
+		if !ok {
+			site = &siteSession{}
+			s.sites[result.Name] = site
+			s.order = append(s.order, result.Name)
+		}
+
+		site.total++
+		site.retries += result.Retries()
+		if result.Success {
+			site.online++
+			site.totalLatency += result.Latency
+			if result.Latency > site.maxLatency {
+				site.maxLatency = result.Latency
+			}
+		}
+	}
+}
+
+// PrintSessionSummary 打印监控会话摘要
+func PrintSessionSummary(s *Session) {
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("📋 监控会话摘要")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("开始时间: %s\n", s.start.Format("2006-01-02 15:04:05"))
+	fmt.Printf("持续时间: %s\n", time.Since(s.start).Round(time.Second))
+	fmt.Printf("完成轮次: %d\n", s.cycles)
+
+	if s.cycles > 0 {
+		fmt.Println()
+		fmt.Printf("%-15s %10s %12s %12s %8s\n", "网站", "可用率", "平均延迟", "最高延迟", "重试")
+		for _, name := range s.order {
+			site := s.sites[name]
+			avg := "-"
+			if site.online > 0 {
+				avg = fmt.Sprintf("%d ms", (site.totalLatency / time.Duration(site.online)).Milliseconds())
+			}
+			fmt.Printf("%-15s %9.1f%% %12s %12s %8d\n",
+				name,
+				float64(site.online)/float64(site.total)*100,
+				avg,
+				formatPhase(site.maxLatency),
+				site.retries,
+			)
+		}
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/icarus-go/netspeed/pkg/tester"
@@ -9,53 +10,308 @@ import (
 
 // PrintResultsTable 以表格形式输出结果
 func PrintResultsTable(results []tester.TestResult) {
-	// 表头
+	printTableHeader()
+	for _, result := range results {
+		printTableRow(result)
+	}
+	printTableFooter()
+	printNotes(results)
+}
+
+// printTableHeader 输出结果表的表头
+func printTableHeader() {
 	fmt.Println("┌─────────────────┬──────────────┬────────────────────────────┬──────────┐")
 	fmt.Printf("│ %-15s │ %-12s │ %-26s │ %-8s │\n", "网站", "延迟", "URL", "状态")
 	fmt.Println("├─────────────────┼──────────────┼────────────────────────────┼──────────┤")
+}
 
-	// 数据行
-	for _, result := range results {
-		var statusIcon string
-		var latencyStr string
+// printTableRow 输出结果表的一行
+func printTableRow(result tester.TestResult) {
+	var statusIcon string
+	var latencyStr string
 
-		if result.Success {
+	if result.Success {
+		statusIcon = "✓"
+		latencyStr = fmt.Sprintf("%6d ms", result.Latency.Milliseconds())
+
+		// 根据状态着色
+		switch result.Status {
+		case "优秀":
 			statusIcon = "✓"
+		case "良好":
+			statusIcon = "✓"
+		case "一般":
+			statusIcon = "⚠"
+		case "较差":
+			statusIcon = "!"
+		case "降级":
+			statusIcon = "⚠"
+		}
+	} else if result.Canceled() {
+		statusIcon = "○"
+		latencyStr = fmt.Sprintf("%10s", "-")
+	} else {
+		statusIcon = "✗"
+		latencyStr = fmt.Sprintf("%10s", failureCode(result))
+		if result.Latency > 0 {
+			// 收到了响应但未通过成功条件
 			latencyStr = fmt.Sprintf("%6d ms", result.Latency.Milliseconds())
+		}
+	}
+
+	// 截断 URL
+	url := result.URL
+	if len(url) > 26 {
+		url = url[:23] + "..."
+	}
+
+	fmt.Printf("│ %s %-13s │ %-12s │ %-26s │ %s %-6s │\n",
+		statusIcon,
+		result.Name,
+		latencyStr,
+		url,
+		statusIcon,
+		result.Status,
+	)
+}
+
+// printTableFooter 输出结果表的表尾
+func printTableFooter() {
+	fmt.Println("└─────────────────┴──────────────┴────────────────────────────┴──────────┘")
+}
+
+// PrintResultsTableDetailed 以表格形式输出结果，并逐列显示各阶段耗时
+func PrintResultsTableDetailed(results []tester.TestResult) {
+	printDetailedHeader()
+	for _, result := range results {
+		printDetailedRow(result)
+	}
+	printDetailedFooter()
+	printNotes(results)
+	printRedirects(results)
+}
+
+// printDetailedHeader 输出阶段耗时表的表头
+func printDetailedHeader() {
+	fmt.Println("┌─────────────────┬──────────┬──────────┬──────────┬──────────┬──────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-8s │ %-8s │ %-8s │ %-8s │ %-8s │ %-8s │\n", "网站", "DNS", "连接", "TLS", "首字节", "总计", "状态")
+	fmt.Println("├─────────────────┼──────────┼──────────┼──────────┼──────────┼──────────┼──────────┤")
+}
+
+// printDetailedRow 输出阶段耗时表的一行
+func printDetailedRow(result tester.TestResult) {
+	if !result.Success && result.Latency == 0 {
+		statusIcon := "✗"
+		if result.Canceled() {
+			statusIcon = "○"
+		}
+		fmt.Printf("│ %s %-13s │ %8s │ %8s │ %8s │ %8s │ %8s │ %s %-6s │\n",
+			statusIcon, result.Name, "-", "-", "-", "-", failureCode(result), statusIcon, result.Status)
+		return
+	}
+
+	statusIcon := "✓"
+	if !result.Success {
+		statusIcon = "✗"
+	} else if result.Degraded {
+		statusIcon = "⚠"
+	}
+
+	timing := result.Timing
+	dns, connect, tls := formatPhase(timing.DNS), formatPhase(timing.Connect), formatPhase(timing.TLS)
+	if result.Reused {
+		// 复用连接，没有 DNS/连接/TLS 阶段
+		dns, connect, tls = "复用", "复用", "复用"
+	}
+	fmt.Printf("│ %s %-13s │ %8s │ %8s │ %8s │ %8s │ %8s │ %s %-6s │\n",
+		statusIcon,
+		result.Name,
+		dns,
+		connect,
+		tls,
+		formatPhase(timing.TTFB),
+		formatPhase(timing.Total),
+		statusIcon,
+		result.Status,
+	)
+}
+
+// printDetailedFooter 输出阶段耗时表的表尾
+func printDetailedFooter() {
+	fmt.Println("└─────────────────┴──────────┴──────────┴──────────┴──────────┴──────────┴──────────┘")
+}
 
-			// 根据状态着色
-			switch result.Status {
-			case "优秀":
-				statusIcon = "✓"
-			case "良好":
-				statusIcon = "✓"
-			case "一般":
-				statusIcon = "⚠"
-			case "较差":
-				statusIcon = "!"
+// failureCode 返回失败结果的分类代码，用于延迟列；没有分类（已取消）时返回 "-"
+func failureCode(result tester.TestResult) string {
+	if result.Category == tester.ErrorNone {
+		return "-"
+	}
+	return string(result.Category)
+}
+
+// printNotes 在表格下方列出未通过成功条件、降级和经过重试的站点
+func printNotes(results []tester.TestResult) {
+	for _, result := range results {
+		if result.FailedAssertion != "" {
+			fmt.Printf("  ✗ %s: %s\n", result.Name, result.FailedAssertion)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Printf("  ⚠ %s: %s\n", result.Name, warning)
+		}
+		if retries := result.Retries(); retries > 0 {
+			fmt.Printf("  ↻ %s: 重试 %d 次\n", result.Name, retries)
+		}
+	}
+}
+
+// printRedirects 列出发生了重定向的站点的完整跳转链，⚠ 标记跳转到非预期域名的一跳
+func printRedirects(results []tester.TestResult) {
+	for _, result := range results {
+		if len(result.Redirects) == 0 {
+			continue
+		}
+
+		hops := make([]string, 0, len(result.Redirects))
+		for _, hop := range result.Redirects {
+			mark := ""
+			if hop.Unexpected {
+				mark = "⚠ "
 			}
-		} else {
-			statusIcon = "✗"
-			latencyStr = "   Timeout"
+			hops = append(hops, fmt.Sprintf("%s%s (%d, %s)", mark, hop.URL, hop.StatusCode, formatPhase(hop.Latency)))
 		}
+		fmt.Printf("  ↪ %s: %s\n", result.Name, strings.Join(hops, " → "))
+	}
+}
+
+// PrintStatsTable 以表格形式输出多次采样的延迟统计
+func PrintStatsTable(results []tester.TestResult) {
+	// 表头
+	fmt.Println("┌─────────────────┬──────────┬──────────┬──────────┬──────────┬──────────┬────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-8s │ %-8s │ %-8s │ %-8s │ %-8s │ %-6s │ %-8s │\n", "网站", "最低", "中位数", "P95", "最高", "抖动", "失败率", "状态")
+	fmt.Println("├─────────────────┼──────────┼──────────┼──────────┼──────────┼──────────┼────────┼──────────┤")
 
-		// 截断 URL
-		url := result.URL
-		if len(url) > 26 {
-			url = url[:23] + "..."
+	// 数据行
+	for _, result := range results {
+		stats := result.Stats
+		statusIcon := "✓"
+		if !result.Success {
+			statusIcon = "✗"
+		} else if stats.FailureRatio > 0 {
+			statusIcon = "⚠"
 		}
 
-		fmt.Printf("│ %s %-13s │ %-12s │ %-26s │ %s %-6s │\n",
+		fmt.Printf("│ %s %-13s │ %8s │ %8s │ %8s │ %8s │ %8s │ %5.0f%% │ %s %-6s │\n",
 			statusIcon,
 			result.Name,
-			latencyStr,
-			url,
+			formatPhase(stats.Min),
+			formatPhase(stats.Median),
+			formatPhase(stats.P95),
+			formatPhase(stats.Max),
+			formatPhase(stats.Jitter),
+			stats.FailureRatio*100,
 			statusIcon,
 			result.Status,
 		)
 	}
 
-	fmt.Println("└─────────────────┴──────────────┴────────────────────────────┴──────────┘")
+	fmt.Println("└─────────────────┴──────────┴──────────┴──────────┴──────────┴──────────┴────────┴──────────┘")
+}
+
+// PrintConnTable 以表格形式对比新建连接（冷）与复用连接（热）的延迟
+func PrintConnTable(results []tester.TestResult) {
+	// 表头
+	fmt.Println("┌─────────────────┬──────────┬──────────┬──────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-8s │ %-8s │ %-8s │ %-8s │\n", "网站", "冷连接", "热连接", "差值", "状态")
+	fmt.Println("├─────────────────┼──────────┼──────────┼──────────┼──────────┤")
+
+	// 数据行
+	for _, result := range results {
+		if !result.Success {
+			fmt.Printf("│ ✗ %-13s │ %8s │ %8s │ %8s │ ✗ %-6s │\n",
+				result.Name, "-", "-", "-", result.Status)
+			continue
+		}
+
+		warm, diff := "-", "-"
+		if result.WarmLatency > 0 {
+			warm = formatPhase(result.WarmLatency)
+			diff = formatPhase(result.Latency - result.WarmLatency)
+		}
+		fmt.Printf("│ ✓ %-13s │ %8s │ %8s │ %8s │ ✓ %-6s │\n",
+			result.Name,
+			formatPhase(result.Latency),
+			warm,
+			diff,
+			result.Status,
+		)
+	}
+
+	fmt.Println("└─────────────────┴──────────┴──────────┴──────────┴──────────┘")
+}
+
+// PrintThroughputTable 以表格形式输出吞吐测试结果
+func PrintThroughputTable(results []tester.TestResult) {
+	// 表头
+	fmt.Println("┌─────────────────┬──────┬──────────────┬────────────┬──────────┬────────┬────────┬──────────┐")
+	fmt.Printf("│ %-15s │ %-4s │ %-12s │ %-10s │ %-8s │ %-6s │ %-6s │ %-8s │\n", "网站", "方向", "速度", "传输量", "爬坡", "稳定度", "连接数", "状态")
+	fmt.Println("├─────────────────┼──────┼──────────────┼────────────┼──────────┼────────┼────────┼──────────┤")
+
+	// 数据行
+	for _, result := range results {
+		tp := result.Throughput
+		if !result.Success || tp == nil {
+			fmt.Printf("│ ✗ %-13s │ %-4s │ %12s │ %10s │ %8s │ %6s │ %6s │ ✗ %-6s │\n",
+				result.Name, "-", "-", "-", "-", "-", "-", result.Status)
+			continue
+		}
+
+		fmt.Printf("│ ✓ %-13s │ %-4s │ %7.1f Mbps │ %10s │ %8s │ %5.0f%% │ %6d │ ✓ %-6s │\n",
+			result.Name,
+			directionLabel(tp.Direction),
+			tp.Mbps,
+			formatBytes(tp.Bytes),
+			formatPhase(tp.RampUp),
+			tp.Stability,
+			tp.Streams,
+			result.Status,
+		)
+	}
+
+	fmt.Println("└─────────────────┴──────┴──────────────┴────────────┴──────────┴────────┴────────┴──────────┘")
+}
+
+// directionLabel 返回吞吐方向的中文名称
+func directionLabel(direction string) string {
+	switch direction {
+	case tester.DirectionDownload:
+		return "下载"
+	case tester.DirectionUpload:
+		return "上传"
+	default:
+		return direction
+	}
+}
+
+// formatBytes 格式化字节数
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.2f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// formatPhase 格式化阶段耗时，未发生的阶段（如连接复用）显示为 "-"
+func formatPhase(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d ms", d.Milliseconds())
 }
 
 // PrintSummary 打印统计摘要
@@ -64,11 +320,37 @@ func PrintSummary(results []tester.TestResult) {
 	var totalLatency time.Duration
 	var minLatency, maxLatency time.Duration
 	var minSite, maxSite string
+	var sampled int
+	var totalJitter time.Duration
+	var totalFailureRatio float64
+	var downloads, uploads int
+	var totalDownMbps, totalUpMbps float64
+	failures := make(map[tester.ErrorCategory]int)
 
 	total = len(results)
 	minLatency = time.Hour // 初始值设为很大
 
 	for _, result := range results {
+		if result.Stats.Samples > 1 {
+			sampled++
+			totalJitter += result.Stats.Jitter
+			totalFailureRatio += result.Stats.FailureRatio
+		}
+		if result.Success && result.Throughput != nil {
+			switch result.Throughput.Direction {
+			case tester.DirectionDownload:
+				downloads++
+				totalDownMbps += result.Throughput.Mbps
+			case tester.DirectionUpload:
+				uploads++
+				totalUpMbps += result.Throughput.Mbps
+			}
+		}
+
+		if !result.Success && result.Category != tester.ErrorNone {
+			failures[result.Category]++
+		}
+
 		if result.Success {
 			online++
 			totalLatency += result.Latency
@@ -88,7 +370,9 @@ func PrintSummary(results []tester.TestResult) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println("📊 统计摘要")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("在线网站: %d/%d (%.1f%%)\n", online, total, float64(online)/float64(total)*100)
+	if total > 0 {
+		fmt.Printf("在线网站: %d/%d (%.1f%%)\n", online, total, float64(online)/float64(total)*100)
+	}
 
 	if online > 0 {
 		avgLatency := totalLatency / time.Duration(online)
@@ -96,14 +380,41 @@ func PrintSummary(results []tester.TestResult) {
 		fmt.Printf("最低延迟: %d ms (%s)\n", minLatency.Milliseconds(), minSite)
 		fmt.Printf("最高延迟: %d ms (%s)\n", maxLatency.Milliseconds(), maxSite)
 
-		// 网络质量评级
-		quality := "优秀"
-		if avgLatency.Milliseconds() > 500 {
-			quality = "一般"
-		} else if avgLatency.Milliseconds() > 200 {
-			quality = "良好"
+		// 网络质量评级，与各站点的状态使用同一套评级
+		if quality := tester.OverallLevel(results); quality != tester.LevelNone {
+			fmt.Printf("网络质量: %s\n", quality)
 		}
-		fmt.Printf("网络质量: %s\n", quality)
+	}
+
+	if len(failures) > 0 {
+		fmt.Printf("失败分类: %s\n", formatFailures(failures))
+	}
+
+	if health := ipv6Health(results); health != "" {
+		fmt.Printf("IPv6 状态: %s\n", health)
+	}
+
+	if sampled > 0 {
+		fmt.Printf("平均抖动: %d ms\n", (totalJitter / time.Duration(sampled)).Milliseconds())
+		fmt.Printf("平均失败率: %.1f%%\n", totalFailureRatio/float64(sampled)*100)
+	}
+
+	if downloads > 0 {
+		fmt.Printf("平均下载: %.1f Mbps\n", totalDownMbps/float64(downloads))
+	}
+	if uploads > 0 {
+		fmt.Printf("平均上传: %.1f Mbps\n", totalUpMbps/float64(uploads))
 	}
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
+
+// formatFailures 按固定顺序格式化各失败分类的数量，如 "超时 2, 连接重置 1"
+func formatFailures(failures map[tester.ErrorCategory]int) string {
+	parts := make([]string, 0, len(failures))
+	for _, category := range tester.ErrorCategories {
+		if n := failures[category]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", category.Label(), n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
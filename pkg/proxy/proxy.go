@@ -1,7 +1,9 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -12,11 +14,13 @@ import (
 
 // InitHTTPClient 初始化 HTTP 客户端，支持代理配置
 // 支持通过参数 -proxy 或环境变量 (HTTP_PROXY, HTTPS_PROXY, ALL_PROXY) 设置代理
+// 不输出代理信息，调用方在确定最终使用的代理后调用 PrintInfo
 func InitHTTPClient(proxyURL string, timeout time.Duration) (*http.Client, error) {
 	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     30 * time.Second,
+		MaxIdleConns:           100,
+		MaxIdleConnsPerHost:    10,
+		IdleConnTimeout:        30 * time.Second,
+		OnProxyConnectResponse: checkConnectResponse,
 	}
 
 	// 检查是否通过参数设置了代理
@@ -31,7 +35,6 @@ func InitHTTPClient(proxyURL string, timeout time.Duration) (*http.Client, error
 		switch parsedURL.Scheme {
 		case "http", "https":
 			transport.Proxy = http.ProxyURL(parsedURL)
-			fmt.Printf("✓ 已设置 HTTP/HTTPS 代理: %s\n", proxyURL)
 
 		case "socks5":
 			// SOCKS5 代理
@@ -40,32 +43,13 @@ func InitHTTPClient(proxyURL string, timeout time.Duration) (*http.Client, error
 				return nil, fmt.Errorf("SOCKS5 代理配置失败: %v", err)
 			}
 			transport.Dial = dialer.Dial
-			fmt.Printf("✓ 已设置 SOCKS5 代理: %s\n", proxyURL)
 
 		default:
 			return nil, fmt.Errorf("不支持的代理协议: %s (支持 http, https, socks5)", parsedURL.Scheme)
 		}
-	} else {
-		// 检查环境变量代理设置
-		httpProxy := getEnvProxy("HTTP_PROXY", "http_proxy")
-		httpsProxy := getEnvProxy("HTTPS_PROXY", "https_proxy")
-		allProxy := getEnvProxy("ALL_PROXY", "all_proxy")
-
-		if httpProxy != "" || httpsProxy != "" || allProxy != "" {
-			// 使用环境变量代理，让 Go 的 http 库自动读取
-			// transport.Proxy 为 nil 时，Go 会自动检查环境变量 HTTP_PROXY, HTTPS_PROXY, ALL_PROXY
-			fmt.Println("✓ 已自动检测到环境变量代理配置:")
-			if httpProxy != "" {
-				fmt.Printf("  - HTTP_PROXY: %s\n", httpProxy)
-			}
-			if httpsProxy != "" {
-				fmt.Printf("  - HTTPS_PROXY: %s\n", httpsProxy)
-			}
-			if allProxy != "" {
-				fmt.Printf("  - ALL_PROXY: %s\n", allProxy)
-			}
-			transport.Proxy = http.ProxyFromEnvironment
-		}
+	} else if Configured("") {
+		// 使用环境变量代理（HTTP_PROXY, HTTPS_PROXY, ALL_PROXY），让 Go 的 http 库自动读取
+		transport.Proxy = http.ProxyFromEnvironment
 	}
 
 	return &http.Client{
@@ -74,6 +58,91 @@ func InitHTTPClient(proxyURL string, timeout time.Duration) (*http.Client, error
 	}, nil
 }
 
+// PrintInfo 输出使用的代理（参数 -proxy 或环境变量），未配置代理时不输出
+func PrintInfo(proxyURL string) {
+	if proxyURL != "" {
+		if u, err := url.Parse(proxyURL); err == nil && u.Scheme == "socks5" {
+			fmt.Printf("✓ 已设置 SOCKS5 代理: %s\n", proxyURL)
+		} else {
+			fmt.Printf("✓ 已设置 HTTP/HTTPS 代理: %s\n", proxyURL)
+		}
+		return
+	}
+
+	httpProxy := getEnvProxy("HTTP_PROXY", "http_proxy")
+	httpsProxy := getEnvProxy("HTTPS_PROXY", "https_proxy")
+	allProxy := getEnvProxy("ALL_PROXY", "all_proxy")
+	if httpProxy == "" && httpsProxy == "" && allProxy == "" {
+		return
+	}
+	fmt.Println("✓ 已自动检测到环境变量代理配置:")
+	if httpProxy != "" {
+		fmt.Printf("  - HTTP_PROXY: %s\n", httpProxy)
+	}
+	if httpsProxy != "" {
+		fmt.Printf("  - HTTPS_PROXY: %s\n", httpsProxy)
+	}
+	if allProxy != "" {
+		fmt.Printf("  - ALL_PROXY: %s\n", allProxy)
+	}
+}
+
+// ConnectError HTTP 代理拒绝 CONNECT 请求（如 407 需要认证、502 无法连接目标站点）
+type ConnectError struct {
+	StatusCode int    // 代理返回的状态码
+	Status     string // 代理返回的状态行，如 "407 Proxy Authentication Required"
+}
+
+func (e *ConnectError) Error() string {
+	return "代理拒绝连接: " + e.Status
+}
+
+// checkConnectResponse 将代理对 CONNECT 请求的非 200 响应转为 *ConnectError，便于调用方按类型判断失败原因
+func checkConnectResponse(ctx context.Context, proxyURL *url.URL, req *http.Request, resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return &ConnectError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
+
+// InitDialContext 返回经由 SOCKS5 代理建立连接的拨号函数，供 TCP/TLS/DNS 探测使用
+// 代理来自参数 -proxy 或环境变量 ALL_PROXY；未配置 SOCKS5 代理时返回 nil（直连）
+func InitDialContext(proxyURL string) (func(ctx context.Context, network, address string) (net.Conn, error), error) {
+	if proxyURL == "" {
+		proxyURL = getEnvProxy("ALL_PROXY", "all_proxy")
+	}
+	if proxyURL == "" {
+		return nil, nil
+	}
+
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的代理 URL: %v", err)
+	}
+	if parsedURL.Scheme != "socks5" {
+		// HTTP 代理无法转发任意 TCP 连接
+		return nil, nil
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", parsedURL.Host, nil, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("SOCKS5 代理配置失败: %v", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("SOCKS5 代理不支持 context 拨号")
+	}
+	return contextDialer.DialContext, nil
+}
+
+// Configured 是否配置了代理（参数 -proxy 或环境变量 HTTP_PROXY、HTTPS_PROXY、ALL_PROXY）
+func Configured(proxyURL string) bool {
+	return proxyURL != "" ||
+		getEnvProxy("HTTP_PROXY", "http_proxy") != "" ||
+		getEnvProxy("HTTPS_PROXY", "https_proxy") != "" ||
+		getEnvProxy("ALL_PROXY", "all_proxy") != ""
+}
+
 // getEnvProxy 获取环境变量代理，优先检查大写，再检查小写
 func getEnvProxy(upper, lower string) string {
 	if val := os.Getenv(upper); val != "" {
@@ -81,3 +150,40 @@ func getEnvProxy(upper, lower string) string {
 	}
 	return os.Getenv(lower)
 }
+
+// ForceFamily 返回只通过指定网络类型（tcp4 / tcp6）建立直连的客户端副本
+// 经由代理时不能限定目标站点的地址族：HTTP 代理只限制到代理的连接，
+// SOCKS5 代理自行拨号且由代理解析目标地址，两者都由代理决定目标的地址族，调用方应在配置代理时拒绝使用
+func ForceFamily(client *http.Client, network string) *http.Client {
+	c := *client
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return &c
+	}
+	transport = transport.Clone()
+
+	dial := transport.DialContext
+	if dial == nil && transport.Dial != nil {
+		legacy := transport.Dial
+		dial = func(ctx context.Context, n, addr string) (net.Conn, error) {
+			return legacy(n, addr)
+		}
+	}
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, n, addr string) (net.Conn, error) {
+		if n == "tcp" {
+			n = network
+		}
+		return dial(ctx, n, addr)
+	}
+	c.Transport = transport
+	return &c
+}
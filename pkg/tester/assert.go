@@ -0,0 +1,104 @@
+package tester
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxAssertBody 校验响应体时最多读取的字节数
+const maxAssertBody = 1 << 20
+
+// hasAssertions 站点是否配置了成功条件
+func (s Site) hasAssertions() bool {
+	return len(s.ExpectStatus) > 0 || len(s.ExpectHeaders) > 0 || s.needsBody()
+}
+
+// needsBody 站点的成功条件是否需要读取响应体
+func (s Site) needsBody() bool {
+	return s.BodyContains != "" || s.BodyRegex != ""
+}
+
+// checkResponse 按站点配置的成功条件校验响应，返回未通过的条件描述，全部通过时返回空字符串
+func checkResponse(site Site, resp *http.Response) string {
+	if len(site.ExpectStatus) > 0 && !matchStatus(site.ExpectStatus, resp.StatusCode) {
+		return fmt.Sprintf("状态码 %d 不在 %s 中", resp.StatusCode, strings.Join(site.ExpectStatus, ","))
+	}
+
+	names := make([]string, 0, len(site.ExpectHeaders))
+	for name := range site.ExpectHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		want := site.ExpectHeaders[name]
+		got := resp.Header.Get(name)
+		if got == "" {
+			return fmt.Sprintf("缺少响应头 %s", name)
+		}
+		if want != "" && !strings.Contains(got, want) {
+			return fmt.Sprintf("响应头 %s 不包含 %q", name, want)
+		}
+	}
+
+	if !site.needsBody() {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAssertBody))
+	if err != nil {
+		return fmt.Sprintf("读取响应体失败: %v", err)
+	}
+
+	if site.BodyContains != "" && !strings.Contains(string(body), site.BodyContains) {
+		return fmt.Sprintf("响应体不包含 %q", site.BodyContains)
+	}
+
+	if site.BodyRegex != "" {
+		re, err := regexp.Compile(site.BodyRegex)
+		if err != nil {
+			return fmt.Sprintf("BodyRegex 无效: %v", err)
+		}
+		if !re.Match(body) {
+			return fmt.Sprintf("响应体不匹配 /%s/", site.BodyRegex)
+		}
+	}
+
+	return ""
+}
+
+// matchStatus 判断状态码是否符合期望，支持 "200"、"200-299" 和 "2xx" 三种写法
+func matchStatus(patterns []string, code int) bool {
+	for _, p := range patterns {
+		p = strings.TrimSpace(strings.ToLower(p))
+
+		// 2xx 形式
+		if len(p) == 3 && strings.HasSuffix(p, "xx") {
+			if class, err := strconv.Atoi(p[:1]); err == nil && code/100 == class {
+				return true
+			}
+			continue
+		}
+
+		// 200-299 形式
+		if lo, hi, ok := strings.Cut(p, "-"); ok {
+			low, err1 := strconv.Atoi(strings.TrimSpace(lo))
+			high, err2 := strconv.Atoi(strings.TrimSpace(hi))
+			if err1 == nil && err2 == nil && code >= low && code <= high {
+				return true
+			}
+			continue
+		}
+
+		// 单个状态码
+		if want, err := strconv.Atoi(p); err == nil && code == want {
+			return true
+		}
+	}
+	return false
+}
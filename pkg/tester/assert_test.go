@@ -0,0 +1,118 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestMatchStatus 测试状态码匹配
+func TestMatchStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		code     int
+		want     bool
+	}{
+		{"单个状态码", []string{"200"}, 200, true},
+		{"单个状态码不匹配", []string{"200"}, 204, false},
+		{"范围", []string{"200-299"}, 204, true},
+		{"范围外", []string{"200-299"}, 302, false},
+		{"状态类", []string{"2xx"}, 201, true},
+		{"状态类大写", []string{"3XX"}, 301, true},
+		{"状态类不匹配", []string{"2xx"}, 403, false},
+		{"多个条件", []string{"200", "3xx"}, 302, true},
+		{"无效写法", []string{"abc"}, 200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchStatus(tt.patterns, tt.code); got != tt.want {
+				t.Errorf("matchStatus(%v, %d) = %v, want %v", tt.patterns, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_Assertions 测试成功条件校验
+func TestTester_TestSite_Assertions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx/1.25")
+		w.Write([]byte("<html>Welcome to example, build 1024</html>"))
+	})
+	mux.HandleFunc("/portal", func(w http.ResponseWriter, r *http.Request) {
+		// 模拟强制门户：返回 200 但内容是登录页
+		w.Write([]byte("<html>Please login to continue</html>"))
+	})
+	mux.HandleFunc("/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		// 不支持 HEAD 的服务器
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Server", "nginx/1.25")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		site   Site
+		wantOK bool
+	}{
+		{"全部通过", Site{URL: server.URL + "/ok", ExpectStatus: []string{"2xx"}, BodyContains: "Welcome", BodyRegex: `build \d+`, ExpectHeaders: map[string]string{"Server": "nginx"}}, true},
+		{"强制门户", Site{URL: server.URL + "/portal", BodyContains: "Welcome"}, false},
+		{"状态码不符", Site{URL: server.URL + "/forbidden", ExpectStatus: []string{"200-299"}}, false},
+		{"未配置时 403 视为在线", Site{URL: server.URL + "/forbidden"}, true},
+		{"缺少响应头", Site{URL: server.URL + "/ok", ExpectHeaders: map[string]string{"X-Cache": ""}}, false},
+		{"正则不匹配", Site{URL: server.URL + "/ok", BodyRegex: `^build`}, false},
+		{"不支持 HEAD", Site{URL: server.URL + "/no-head", ExpectStatus: []string{"2xx"}, ExpectHeaders: map[string]string{"Server": "nginx"}}, true},
+	}
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.site.Name = "local"
+			result := tr.TestSite(context.Background(), tt.site)
+
+			if result.Success != tt.wantOK {
+				t.Fatalf("Success = %v, want %v (status=%s, error=%s)", result.Success, tt.wantOK, result.Status, result.Error)
+			}
+			if !tt.wantOK {
+				if result.FailedAssertion == "" {
+					t.Error("FailedAssertion should not be empty")
+				}
+				if result.Status != "校验失败" {
+					t.Errorf("Status = %s, want 校验失败", result.Status)
+				}
+				if result.StatusCode == 0 {
+					t.Error("StatusCode should be recorded")
+				}
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_AssertionCert 测试校验失败时仍记录证书信息
+func TestTester_TestSite_AssertionCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	result := tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL, ExpectStatus: []string{"2xx"}})
+
+	if result.Success || result.FailedAssertion == "" {
+		t.Fatalf("TestSite() should fail the assertion (status=%s)", result.Status)
+	}
+	if result.Cert == nil {
+		t.Error("Cert should be recorded when an assertion fails")
+	}
+}
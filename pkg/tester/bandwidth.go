@@ -0,0 +1,351 @@
+package tester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sampleInterval 吞吐采样间隔
+const sampleInterval = 100 * time.Millisecond
+
+// 吞吐测试方向
+const (
+	DirectionDownload = "download"
+	DirectionUpload   = "upload"
+)
+
+// errStreamDone 连接已完成分配给它的传输量，正常结束
+var errStreamDone = errors.New("stream done")
+
+// Throughput 吞吐测试结果
+type Throughput struct {
+	Direction string        // 方向: download / upload
+	Bytes     int64         // 传输字节数（上传为服务器已确认的字节数）
+	Duration  time.Duration // 实际测试时长
+	Mbps      float64       // 爬坡结束后的稳定速率
+	RampUp    time.Duration // 爬坡时间（速率首次达到稳定值 80% 所需时间）
+	Stability float64       // 稳定度 0-100，越高速率越平稳
+	Streams   int           // 并行连接数
+}
+
+// BandwidthOptions 吞吐测试参数
+type BandwidthOptions struct {
+	Duration time.Duration // 测试时长
+	Bytes    int64         // 传输字节上限（0 表示不限，与时长任一达到即停止）
+	Streams  int           // 并行连接数
+}
+
+// DefaultBandwidthOptions 默认吞吐测试参数
+var DefaultBandwidthOptions = BandwidthOptions{
+	Duration: 10 * time.Second,
+	Streams:  4,
+}
+
+// streamFunc 单个连接上的一次传输，正常结束返回 nil，调用方会继续发起下一次传输
+// 返回 errStreamDone 表示该连接不再需要传输
+type streamFunc func(ctx context.Context, m *throughputMeter) error
+
+// throughputMeter 在多个连接间累计传输字节数
+type throughputMeter struct {
+	total  atomic.Int64
+	limit  int64
+	cancel context.CancelFunc
+
+	ttfbOnce sync.Once
+	ttfb     time.Duration
+
+	mu    sync.Mutex
+	spans []ackSpan // 已确认的传输（上传），为空时使用实时采样
+}
+
+// ackSpan 一次得到服务器确认的传输
+type ackSpan struct {
+	start, end time.Time
+	bytes      int64
+}
+
+// add 累加传输字节数，达到上限时结束测试
+func (m *throughputMeter) add(n int64) {
+	if m.total.Add(n) >= m.limit && m.limit > 0 {
+		m.cancel()
+	}
+}
+
+// ack 记录一次已被服务器确认的传输，字节数在采样中按请求持续时间平均分摊
+func (m *throughputMeter) ack(start time.Time, n int64) {
+	m.mu.Lock()
+	m.spans = append(m.spans, ackSpan{start: start, end: time.Now(), bytes: n})
+	m.mu.Unlock()
+	m.add(n)
+}
+
+// ackedSamples 根据已确认的传输重建累计字节采样，返回采样和最后一次确认距开始的时间
+// 未得到确认（被中断或失败）的传输不计入，也不计入时长
+func (m *throughputMeter) ackedSamples(begin time.Time) ([]int64, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var last time.Duration
+	for _, span := range m.spans {
+		if end := span.end.Sub(begin); end > last {
+			last = end
+		}
+	}
+
+	acked := make([]float64, int(last/sampleInterval))
+	for _, span := range m.spans {
+		from, d := span.start.Sub(begin), span.end.Sub(span.start)
+		if d <= 0 {
+			d = 1
+		}
+		for i := range acked {
+			covered := time.Duration(i+1)*sampleInterval - from
+			covered = max(0, min(covered, d))
+			acked[i] += float64(span.bytes) * float64(covered) / float64(d)
+		}
+	}
+
+	samples := make([]int64, len(acked))
+	for i, b := range acked {
+		samples[i] = int64(b)
+	}
+	return samples, last
+}
+
+// firstByte 记录首个连接的首字节时间
+func (m *throughputMeter) firstByte(d time.Duration) {
+	m.ttfbOnce.Do(func() {
+		m.ttfb = d
+	})
+}
+
+// TestDownload 下载测速：在多个并行连接上持续下载 site.DownloadURL
+func (t *Tester) TestDownload(ctx context.Context, site Site, opts BandwidthOptions) TestResult {
+	result := TestResult{
+		Name:    site.Name,
+		URL:     site.DownloadURL,
+		Success: false,
+	}
+
+	if site.DownloadURL == "" {
+		result.Error = "未配置 DownloadURL"
+		result.Status = "错误"
+		result.Category = ErrorUnknown
+		return result
+	}
+
+	client := t.streamingClient()
+	tp, ttfb, err := t.runThroughput(ctx, opts, func(ctx context.Context, m *throughputMeter) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", site.DownloadURL, nil)
+		if err != nil {
+			return err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("服务器返回错误: %d", resp.StatusCode)
+		}
+		m.firstByte(time.Since(start))
+
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				m.add(int64(n))
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	tp.Direction = DirectionDownload
+	return throughputResult(result, tp, ttfb, err)
+}
+
+// streamingClient 返回不受整体超时限制的客户端副本
+// 吞吐测试的时长由 context 控制，http.Client.Timeout 会截断响应体的读取
+func (t *Tester) streamingClient() *http.Client {
+	client := *t.client
+	client.Timeout = 0
+	return &client
+}
+
+// runThroughput 在 opts.Streams 个并行连接上反复执行 stream，直到达到时长或字节上限
+func (t *Tester) runThroughput(parent context.Context, opts BandwidthOptions, stream streamFunc) (Throughput, time.Duration, error) {
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultBandwidthOptions.Duration
+	}
+	if opts.Streams < 1 {
+		opts.Streams = 1
+	}
+
+	ctx, cancel := context.WithTimeout(parent, opts.Duration)
+	defer cancel()
+
+	meter := &throughputMeter{limit: opts.Bytes, cancel: cancel}
+	start := time.Now()
+
+	// 周期性记录累计字节数，用于计算爬坡时间和稳定度
+	var samples []int64
+	samplerDone := make(chan struct{})
+	go func() {
+		defer close(samplerDone)
+		ticker := time.NewTicker(sampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				samples = append(samples, meter.total.Load())
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var streamErr error
+	for i := 0; i < opts.Streams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if err := stream(ctx, meter); err != nil {
+					if ctx.Err() == nil && !errors.Is(err, errStreamDone) {
+						errOnce.Do(func() { streamErr = err })
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start)
+	cancel()
+	<-samplerDone
+
+	// 按确认计量时（上传），速率只根据已确认的传输计算
+	if len(meter.spans) > 0 {
+		samples, elapsed = meter.ackedSamples(start)
+	}
+
+	total := meter.total.Load()
+	if total == 0 {
+		if streamErr == nil {
+			streamErr = errors.New("未传输任何数据")
+		}
+		return Throughput{}, 0, streamErr
+	}
+
+	tp := analyzeThroughput(samples, total, elapsed)
+	tp.Streams = opts.Streams
+	return tp, meter.ttfb, nil
+}
+
+// throughputResult 将吞吐测试结果填充到 TestResult
+func throughputResult(result TestResult, tp Throughput, ttfb time.Duration, err error) TestResult {
+	if err != nil {
+		return failedResult(result, err)
+	}
+
+	result.Latency = ttfb
+	result.Success = true
+	result.Level = RateMbps(tp.Mbps)
+	result.Status = result.Level.String()
+	result.Throughput = &tp
+	return result
+}
+
+// analyzeThroughput 根据累计字节采样计算速率、爬坡时间和稳定度
+func analyzeThroughput(samples []int64, total int64, elapsed time.Duration) Throughput {
+	tp := Throughput{
+		Bytes:    total,
+		Duration: elapsed,
+		Mbps:     mbps(total, elapsed),
+	}
+
+	if len(samples) < 3 {
+		return tp
+	}
+
+	// 每个采样区间的字节数
+	rates := make([]float64, len(samples))
+	var prev int64
+	for i, s := range samples {
+		rates[i] = float64(s - prev)
+		prev = s
+	}
+
+	// 以后半段的中位数作为稳定速率
+	tail := append([]float64(nil), rates[len(rates)/2:]...)
+	sort.Float64s(tail)
+	steady := tail[len(tail)/2]
+
+	ramp := 0
+	for i, r := range rates {
+		if r >= steady*0.8 {
+			ramp = i
+			break
+		}
+	}
+	tp.RampUp = time.Duration(ramp+1) * sampleInterval
+
+	// 速率只统计爬坡结束后的部分
+	if after := elapsed - tp.RampUp; after > 0 && total > samples[ramp] {
+		tp.Mbps = mbps(total-samples[ramp], after)
+	}
+
+	tp.Stability = stability(rates[ramp+1:])
+	return tp
+}
+
+// mbps 计算速率（兆比特每秒）
+func mbps(bytes int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / d.Seconds() / 1e6
+}
+
+// stability 根据区间速率的变异系数计算稳定度（0-100）
+func stability(rates []float64) float64 {
+	if len(rates) < 2 {
+		return 100
+	}
+
+	var sum float64
+	for _, r := range rates {
+		sum += r
+	}
+	mean := sum / float64(len(rates))
+	if mean == 0 {
+		return 0
+	}
+
+	var variance float64
+	for _, r := range rates {
+		variance += (r - mean) * (r - mean)
+	}
+	cv := math.Sqrt(variance/float64(len(rates))) / mean
+
+	return math.Max(0, 100*(1-cv))
+}
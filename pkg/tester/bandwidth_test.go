@@ -0,0 +1,140 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newDownloadServer 创建持续输出数据的本地下载服务器
+func newDownloadServer(size int, requests *int32) *httptest.Server {
+	chunk := make([]byte, 64*1024)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		for written := 0; written < size; written += len(chunk) {
+			n := len(chunk)
+			if size-written < n {
+				n = size - written
+			}
+			if _, err := w.Write(chunk[:n]); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+// TestTester_TestDownload_Duration 测试按时长下载
+func TestTester_TestDownload_Duration(t *testing.T) {
+	var requests int32
+	server := newDownloadServer(4<<20, &requests)
+	defer server.Close()
+
+	tr := NewTester(server.Client(), time.Second)
+	opts := BandwidthOptions{Duration: 500 * time.Millisecond, Streams: 3}
+	result := tr.TestDownload(context.Background(), Site{Name: "local", DownloadURL: server.URL}, opts)
+
+	if !result.Success {
+		t.Fatalf("TestDownload() failed: %s", result.Error)
+	}
+
+	tp := result.Throughput
+	if tp == nil {
+		t.Fatal("Throughput should not be nil")
+	}
+	if tp.Bytes <= 0 {
+		t.Errorf("Bytes = %d, want > 0", tp.Bytes)
+	}
+	if tp.Mbps <= 0 {
+		t.Errorf("Mbps = %v, want > 0", tp.Mbps)
+	}
+	if tp.Streams != 3 {
+		t.Errorf("Streams = %d, want 3", tp.Streams)
+	}
+	if tp.Duration < 400*time.Millisecond || tp.Duration > 2*time.Second {
+		t.Errorf("Duration = %v, want about 500ms", tp.Duration)
+	}
+	if tp.Stability < 0 || tp.Stability > 100 {
+		t.Errorf("Stability = %v, want within [0, 100]", tp.Stability)
+	}
+	if got := atomic.LoadInt32(&requests); got < 3 {
+		t.Errorf("requests = %d, want >= 3 (one per stream)", got)
+	}
+}
+
+// TestTester_TestDownload_ByteLimit 测试达到传输量上限后提前结束
+func TestTester_TestDownload_ByteLimit(t *testing.T) {
+	var requests int32
+	server := newDownloadServer(64<<20, &requests)
+	defer server.Close()
+
+	tr := NewTester(server.Client(), time.Second)
+	opts := BandwidthOptions{Duration: 10 * time.Second, Bytes: 1 << 20, Streams: 2}
+
+	start := time.Now()
+	result := tr.TestDownload(context.Background(), Site{Name: "local", DownloadURL: server.URL}, opts)
+	elapsed := time.Since(start)
+
+	if !result.Success {
+		t.Fatalf("TestDownload() failed: %s", result.Error)
+	}
+	if result.Throughput.Bytes < 1<<20 {
+		t.Errorf("Bytes = %d, want >= %d", result.Throughput.Bytes, 1<<20)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("elapsed = %v, byte limit should stop the test early", elapsed)
+	}
+}
+
+// TestTester_TestDownload_Errors 测试下载失败
+func TestTester_TestDownload_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), time.Second)
+	opts := BandwidthOptions{Duration: 200 * time.Millisecond, Streams: 1}
+
+	tests := []struct {
+		name string
+		site Site
+	}{
+		{name: "未配置 DownloadURL", site: Site{Name: "none"}},
+		{name: "404", site: Site{Name: "404", DownloadURL: server.URL}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tr.TestDownload(context.Background(), tt.site, opts)
+			if result.Success {
+				t.Error("TestDownload() should fail")
+			}
+			if result.Error == "" {
+				t.Error("Error should be set")
+			}
+		})
+	}
+}
+
+// TestAnalyzeThroughput 测试爬坡时间和稳定度计算
+func TestAnalyzeThroughput(t *testing.T) {
+	// 前两个区间处于爬坡阶段，之后稳定在每区间 1000 字节
+	samples := []int64{100, 400, 1400, 2400, 3400, 4400, 5400}
+	tp := analyzeThroughput(samples, 5400, 700*time.Millisecond)
+
+	if tp.RampUp != 300*time.Millisecond {
+		t.Errorf("RampUp = %v, want 300ms", tp.RampUp)
+	}
+	if tp.Stability != 100 {
+		t.Errorf("Stability = %v, want 100", tp.Stability)
+	}
+	// 爬坡后 400ms 传输 4000 字节 = 0.08 Mbps
+	if tp.Mbps < 0.079 || tp.Mbps > 0.081 {
+		t.Errorf("Mbps = %v, want 0.08", tp.Mbps)
+	}
+}
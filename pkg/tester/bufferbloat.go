@@ -0,0 +1,208 @@
+package tester
+
+import (
+	"context"
+	"time"
+)
+
+// BufferbloatOptions 负载下延迟测试参数
+type BufferbloatOptions struct {
+	Idle     time.Duration // 空闲延迟的测量时长
+	Duration time.Duration // 每个负载阶段（下载、上传）的时长
+	Streams  int           // 负载的并行连接数
+	Interval time.Duration // 延迟采样间隔
+}
+
+// DefaultBufferbloatOptions 默认负载下延迟测试参数
+var DefaultBufferbloatOptions = BufferbloatOptions{
+	Idle:     3 * time.Second,
+	Duration: 10 * time.Second,
+	Streams:  4,
+	Interval: 200 * time.Millisecond,
+}
+
+// Bufferbloat 负载下延迟测试结果
+type Bufferbloat struct {
+	Idle     LatencyStats // 空闲时的延迟
+	Download *LoadPhase   // 下载负载阶段（未配置 DownloadURL 时为 nil）
+	Upload   *LoadPhase   // 上传负载阶段（未配置 UploadURL 时为 nil）
+	Added    time.Duration
+	Grade    string // 缓冲膨胀等级 A+ ~ F，按各负载阶段中最大的附加延迟评定
+}
+
+// LoadPhase 一个负载阶段的结果
+type LoadPhase struct {
+	Latency LatencyStats  // 负载期间的延迟
+	Added   time.Duration // 附加延迟：负载期间与空闲时延迟中位数之差
+	Mbps    float64       // 负载期间的吞吐速率
+	Error   string        // 负载未能建立时的错误信息
+}
+
+// bufferbloatGrades 附加延迟上限与对应等级
+var bufferbloatGrades = []struct {
+	limit time.Duration
+	grade string
+}{
+	{5 * time.Millisecond, "A+"},
+	{30 * time.Millisecond, "A"},
+	{60 * time.Millisecond, "B"},
+	{200 * time.Millisecond, "C"},
+	{400 * time.Millisecond, "D"},
+}
+
+// BufferbloatGrade 根据附加延迟评定缓冲膨胀等级
+func BufferbloatGrade(added time.Duration) string {
+	for _, g := range bufferbloatGrades {
+		if added < g.limit {
+			return g.grade
+		}
+	}
+	return "F"
+}
+
+// TestBufferbloat 负载下延迟测试：先测量 target 的空闲延迟，
+// 再分别用 load 的 DownloadURL/UploadURL 占满下行和上行带宽，同时持续测量 target 的延迟
+func (t *Tester) TestBufferbloat(ctx context.Context, target, load Site, opts BufferbloatOptions) TestResult {
+	opts = opts.withDefaults()
+	result := TestResult{
+		Name:    target.Name,
+		URL:     target.URL,
+		Success: false,
+	}
+
+	if load.DownloadURL == "" && load.UploadURL == "" {
+		result.Error = "未配置 DownloadURL 或 UploadURL"
+		result.Status = "错误"
+		result.Category = ErrorUnknown
+		return result
+	}
+
+	// 空闲延迟
+	idleCtx, cancel := context.WithTimeout(ctx, opts.Idle)
+	idle, last := t.sampleLatency(idleCtx, target, opts.Interval)
+	cancel()
+	if ctx.Err() != nil {
+		return canceledResult(target)
+	}
+	if idle.Samples == 0 {
+		result.Error = "空闲阶段没有完成任何探测"
+		result.Status = "超时"
+		result.Category = ErrorTimeout
+		return result
+	}
+	if idle.FailureRatio == 1 {
+		// 空闲时就无法连接，沿用最后一次探测的错误
+		return last
+	}
+
+	bb := &Bufferbloat{Idle: idle}
+	bandwidth := BandwidthOptions{Duration: opts.Duration, Streams: opts.Streams}
+	if load.DownloadURL != "" {
+		bb.Download = t.loadPhase(ctx, target, idle, opts.Interval, func(ctx context.Context) TestResult {
+			return t.TestDownload(ctx, load, bandwidth)
+		})
+	}
+	if load.UploadURL != "" && ctx.Err() == nil {
+		bb.Upload = t.loadPhase(ctx, target, idle, opts.Interval, func(ctx context.Context) TestResult {
+			return t.TestUpload(ctx, load, bandwidth)
+		})
+	}
+	if ctx.Err() != nil {
+		return canceledResult(target)
+	}
+
+	for _, phase := range []*LoadPhase{bb.Download, bb.Upload} {
+		if phase != nil && phase.Error == "" && phase.Added > bb.Added {
+			bb.Added = phase.Added
+		}
+	}
+	bb.Grade = BufferbloatGrade(bb.Added)
+
+	result.Success = true
+	result.Latency = idle.Median
+	result.Stats = idle
+	result.Status = bb.Grade
+	result.Bufferbloat = bb
+	return result
+}
+
+// withDefaults 用默认值补全未设置的参数
+func (o BufferbloatOptions) withDefaults() BufferbloatOptions {
+	if o.Idle <= 0 {
+		o.Idle = DefaultBufferbloatOptions.Idle
+	}
+	if o.Duration <= 0 {
+		o.Duration = DefaultBufferbloatOptions.Duration
+	}
+	if o.Streams < 1 {
+		o.Streams = DefaultBufferbloatOptions.Streams
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultBufferbloatOptions.Interval
+	}
+	return o
+}
+
+// loadPhase 在 run 产生负载期间持续测量 target 的延迟
+func (t *Tester) loadPhase(ctx context.Context, target Site, idle LatencyStats, interval time.Duration, run func(ctx context.Context) TestResult) *LoadPhase {
+	loadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan TestResult, 1)
+	go func() {
+		done <- run(loadCtx)
+		// 负载结束后停止采样
+		cancel()
+	}()
+
+	latency, _ := t.sampleLatency(loadCtx, target, interval)
+	throughput := <-done
+
+	phase := &LoadPhase{Latency: latency}
+	if !throughput.Success {
+		phase.Error = throughput.Error
+		return phase
+	}
+	if throughput.Throughput != nil {
+		phase.Mbps = throughput.Throughput.Mbps
+	}
+	if latency.Median > idle.Median {
+		phase.Added = latency.Median - idle.Median
+	}
+	return phase
+}
+
+// sampleLatency 按间隔持续探测 target，直到 ctx 结束，返回延迟统计和最后一次探测结果
+// ctx 结束时被中断的那次探测不计入
+func (t *Tester) sampleLatency(ctx context.Context, target Site, interval time.Duration) (LatencyStats, TestResult) {
+	family := t.family
+	if family == FamilyBoth {
+		family = FamilyAny
+	}
+
+	var latencies []time.Duration
+	var last TestResult
+	samples := 0
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		result := t.probeFamily(ctx, target, family)
+		if ctx.Err() != nil {
+			break
+		}
+
+		samples++
+		last = result
+		if result.Success {
+			latencies = append(latencies, result.Latency)
+		}
+
+		select {
+		case <-ctx.Done():
+			return computeStats(latencies, samples), last
+		case <-ticker.C:
+		}
+	}
+	return computeStats(latencies, samples), last
+}
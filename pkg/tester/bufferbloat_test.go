@@ -0,0 +1,113 @@
+package tester
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestBufferbloatGrade 测试按附加延迟评定等级
+func TestBufferbloatGrade(t *testing.T) {
+	tests := []struct {
+		added time.Duration
+		want  string
+	}{
+		{0, "A+"},
+		{20 * time.Millisecond, "A"},
+		{50 * time.Millisecond, "B"},
+		{150 * time.Millisecond, "C"},
+		{300 * time.Millisecond, "D"},
+		{time.Second, "F"},
+	}
+
+	for _, tt := range tests {
+		if got := BufferbloatGrade(tt.added); got != tt.want {
+			t.Errorf("BufferbloatGrade(%v) = %s, want %s", tt.added, got, tt.want)
+		}
+	}
+}
+
+// TestTester_TestBufferbloat 测试负载期间延迟升高时的附加延迟与等级
+func TestTester_TestBufferbloat(t *testing.T) {
+	// 有传输进行时 /ping 额外延迟 100ms，模拟链路缓冲膨胀；传输限速以保证负载期间一直有传输
+	var active atomic.Int32
+	chunk := make([]byte, 32*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ping":
+			if active.Load() > 0 {
+				time.Sleep(100 * time.Millisecond)
+			}
+		case "/download":
+			active.Add(1)
+			defer active.Add(-1)
+			for i := 0; i < 64; i++ {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+				w.(http.Flusher).Flush()
+				time.Sleep(5 * time.Millisecond)
+			}
+		case "/upload":
+			active.Add(1)
+			defer active.Add(-1)
+			for {
+				if _, err := io.CopyN(io.Discard, r.Body, int64(len(chunk))); err != nil {
+					return
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+	}))
+	defer server.Close()
+
+	target := Site{Name: "local", URL: server.URL, Path: "/ping"}
+	load := Site{DownloadURL: server.URL + "/download", UploadURL: server.URL + "/upload"}
+	opts := BufferbloatOptions{
+		Idle:     300 * time.Millisecond,
+		Duration: 600 * time.Millisecond,
+		Streams:  2,
+		Interval: 20 * time.Millisecond,
+	}
+
+	tr := NewTester(server.Client(), 2*time.Second)
+	result := tr.TestBufferbloat(context.Background(), target, load, opts)
+
+	if !result.Success {
+		t.Fatalf("TestBufferbloat() failed: %s", result.Error)
+	}
+	bb := result.Bufferbloat
+	if bb == nil || bb.Download == nil || bb.Upload == nil {
+		t.Fatalf("Bufferbloat = %+v, want idle, download and upload phases", bb)
+	}
+	if bb.Idle.Samples < 2 {
+		t.Errorf("Idle.Samples = %d, want >= 2", bb.Idle.Samples)
+	}
+	for name, phase := range map[string]*LoadPhase{"download": bb.Download, "upload": bb.Upload} {
+		if phase.Error != "" {
+			t.Errorf("%s phase error: %s", name, phase.Error)
+		}
+		if phase.Added < 50*time.Millisecond {
+			t.Errorf("%s Added = %v, want >= 50ms", name, phase.Added)
+		}
+		if phase.Mbps <= 0 {
+			t.Errorf("%s Mbps = %v, want > 0", name, phase.Mbps)
+		}
+	}
+	if bb.Grade != "C" || result.Status != "C" {
+		t.Errorf("Grade = %s, Status = %s, want C", bb.Grade, result.Status)
+	}
+}
+
+// TestTester_TestBufferbloat_NoLoad 测试未配置负载地址时报错
+func TestTester_TestBufferbloat_NoLoad(t *testing.T) {
+	tr := NewTester(http.DefaultClient, time.Second)
+	result := tr.TestBufferbloat(context.Background(), Site{Name: "x", URL: "http://127.0.0.1:1"}, Site{}, BufferbloatOptions{})
+	if result.Success || result.Status != "错误" {
+		t.Errorf("result = %+v, want 错误", result)
+	}
+}
@@ -0,0 +1,130 @@
+package tester
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// CertInfo TLS 连接与服务器证书信息
+type CertInfo struct {
+	Version     string    // 协商的 TLS 版本，如 "TLS 1.3"
+	CipherSuite string    // 加密套件
+	ALPN        string    // 协商的应用层协议，如 "h2"
+	Subject     string    // 叶子证书主体
+	Issuer      string    // 叶子证书签发者
+	SANs        []string  // 证书包含的域名和 IP
+	Chain       []string  // 证书链各证书的主体（叶子证书在前，根证书在后）
+	NotAfter    time.Time // 过期时间
+	DaysLeft    int       // 距过期的天数（已过期为负数）
+}
+
+// newCertInfo 从 TLS 连接状态中提取证书信息，没有证书时返回 nil
+func newCertInfo(state *tls.ConnectionState) *CertInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	leaf := state.PeerCertificates[0]
+	info := &CertInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ALPN:        state.NegotiatedProtocol,
+		Subject:     certName(leaf.Subject.CommonName, leaf.Subject.Organization),
+		Issuer:      certName(leaf.Issuer.CommonName, leaf.Issuer.Organization),
+		SANs:        append([]string(nil), leaf.DNSNames...),
+		NotAfter:    leaf.NotAfter,
+		DaysLeft:    daysLeft(leaf.NotAfter, time.Now()),
+	}
+	for _, ip := range leaf.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+
+	// 优先使用验证通过的证书链，跳过验证时使用服务器发送的证书
+	chain := state.PeerCertificates
+	if len(state.VerifiedChains) > 0 {
+		chain = state.VerifiedChains[0]
+	}
+	for _, cert := range chain {
+		info.Chain = append(info.Chain, certName(cert.Subject.CommonName, cert.Subject.Organization))
+	}
+	if last := chain[len(chain)-1]; !isSelfSigned(last) {
+		// 链中未包含根证书时，补上最后一个证书的签发者
+		info.Chain = append(info.Chain, certName(last.Issuer.CommonName, last.Issuer.Organization))
+	}
+
+	return info
+}
+
+// certName 组合证书名称的 CN 与组织
+func certName(cn string, org []string) string {
+	switch {
+	case cn != "" && len(org) > 0:
+		return fmt.Sprintf("%s (%s)", cn, strings.Join(org, ", "))
+	case cn != "":
+		return cn
+	default:
+		return strings.Join(org, ", ")
+	}
+}
+
+// daysLeft 返回距过期的整天数，向下取整：刚过期不到一天为 -1
+func daysLeft(notAfter, now time.Time) int {
+	return int(math.Floor(notAfter.Sub(now).Hours() / 24))
+}
+
+// isSelfSigned 证书主体与签发者是否相同
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.Subject.String() == cert.Issuer.String()
+}
+
+// certWarnings 检查证书是否即将过期、签发者是否符合预期
+// warnDays 小于等于 0 时不检查过期
+func certWarnings(site Site, info *CertInfo, warnDays int) []string {
+	if info == nil {
+		return nil
+	}
+
+	var warnings []string
+	switch {
+	case warnDays <= 0:
+	case info.DaysLeft < 0:
+		warnings = append(warnings, fmt.Sprintf("证书已过期 %d 天", -info.DaysLeft))
+	case info.DaysLeft < warnDays:
+		warnings = append(warnings, fmt.Sprintf("证书将在 %d 天后过期", info.DaysLeft))
+	}
+	if site.ExpectIssuer != "" && !info.chainsTo(site.ExpectIssuer) {
+		warnings = append(warnings, fmt.Sprintf("证书链不包含 %q，签发者为 %s（可能存在中间人代理）", site.ExpectIssuer, info.Issuer))
+	}
+	return warnings
+}
+
+// chainsTo 证书链中是否有证书的主体包含 issuer（不区分大小写）
+func (c *CertInfo) chainsTo(issuer string) bool {
+	issuer = strings.ToLower(issuer)
+	for _, name := range c.Chain {
+		if strings.Contains(strings.ToLower(name), issuer) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCert 记录证书信息，探测成功但证书存在问题时将结果标记为降级
+func (r *TestResult) applyCert(site Site, state *tls.ConnectionState, warnDays int) {
+	r.Cert = newCertInfo(state)
+	r.checkCert(site, warnDays)
+}
+
+// checkCert 探测成功但已记录的证书存在问题时将结果标记为降级
+func (r *TestResult) checkCert(site Site, warnDays int) {
+	if !r.Success {
+		return
+	}
+	for _, warning := range certWarnings(site, r.Cert, warnDays) {
+		r.degrade(warning)
+	}
+}
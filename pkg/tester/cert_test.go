@@ -0,0 +1,105 @@
+package tester
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTester_TestSite_Cert 测试证书信息采集和告警
+func TestTester_TestSite_Cert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		url          string
+		expectIssuer string
+		warnDays     int
+		wantDegraded bool
+	}{
+		{"正常", server.URL, "", 14, false},
+		{"即将过期", server.URL, "", 1 << 20, true},
+		{"签发者符合", server.URL, "acme", 14, false},
+		{"签发者不符", server.URL, "Let's Encrypt", 14, true},
+		{"TLS 探测", "tls://" + strings.TrimPrefix(server.URL, "https://"), "Let's Encrypt", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTester(server.Client(), 5*time.Second, WithCertWarnDays(tt.warnDays))
+			result := tr.TestSite(context.Background(), Site{Name: "local", URL: tt.url, ExpectIssuer: tt.expectIssuer})
+
+			if !result.Success {
+				t.Fatalf("TestSite() failed: %s", result.Error)
+			}
+
+			cert := result.Cert
+			if cert == nil {
+				t.Fatal("Cert should not be nil")
+			}
+			if !strings.HasPrefix(cert.Version, "TLS") || cert.CipherSuite == "" {
+				t.Errorf("Version = %q, CipherSuite = %q", cert.Version, cert.CipherSuite)
+			}
+			if !slices.Contains(cert.SANs, "example.com") {
+				t.Errorf("SANs = %v, want to contain example.com", cert.SANs)
+			}
+			if cert.DaysLeft <= 0 || len(cert.Chain) == 0 {
+				t.Errorf("DaysLeft = %d, Chain = %v", cert.DaysLeft, cert.Chain)
+			}
+
+			if result.Degraded != tt.wantDegraded {
+				t.Fatalf("Degraded = %v, want %v (warnings=%v)", result.Degraded, tt.wantDegraded, result.Warnings)
+			}
+			if tt.wantDegraded && (result.Status != "降级" || len(result.Warnings) == 0) {
+				t.Errorf("Status = %s, Warnings = %v", result.Status, result.Warnings)
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_CertSamples 测试多次采样时保留降级状态
+func TestTester_TestSite_CertSamples(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second, WithSamples(3), WithCertWarnDays(1<<20))
+	result := tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL})
+
+	if !result.Degraded || result.Status != "降级" {
+		t.Errorf("Degraded = %v, Status = %s", result.Degraded, result.Status)
+	}
+}
+
+// TestNewCertInfo_DaysLeft 测试距过期天数向下取整
+func TestNewCertInfo_DaysLeft(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		want     int
+	}{
+		{"剩余一天多", now.Add(36 * time.Hour), 1},
+		{"剩余不到一天", now.Add(time.Hour), 0},
+		{"刚过期", now.Add(-time.Hour), -1},
+		{"过期一天多", now.Add(-36 * time.Hour), -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysLeft(tt.notAfter, now); got != tt.want {
+				t.Errorf("daysLeft() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	state := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{NotAfter: now.Add(-time.Hour)}}}
+	if info := newCertInfo(state); info.DaysLeft != -1 {
+		t.Errorf("newCertInfo().DaysLeft = %d, want -1 for a just-expired certificate", info.DaysLeft)
+	}
+}
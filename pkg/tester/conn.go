@@ -0,0 +1,66 @@
+package tester
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ConnMode HTTP 探测的连接复用方式
+type ConnMode string
+
+// 连接复用方式
+const (
+	ConnPooled ConnMode = "pooled" // 复用连接池中的空闲连接（默认，后续探测测到的是热连接）
+	ConnCold   ConnMode = "cold"   // 每次探测都建立新连接，与首次打开页面的浏览器一致
+	ConnBoth   ConnMode = "both"   // 分别测量冷连接和热连接的延迟
+)
+
+// ParseConnMode 解析连接复用方式，空字符串视为 pooled
+func ParseConnMode(s string) (ConnMode, error) {
+	switch mode := ConnMode(strings.ToLower(s)); mode {
+	case "":
+		return ConnPooled, nil
+	case ConnPooled, ConnCold, ConnBoth:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("不支持的连接方式: %s (支持 pooled, cold, both)", s)
+	}
+}
+
+// WithConnMode 设置 HTTP 探测的连接复用方式
+func WithConnMode(mode ConnMode) Option {
+	return func(t *Tester) {
+		t.connMode = mode
+	}
+}
+
+// coldClient 返回禁用连接复用的客户端副本，每次请求都重新拨号
+func coldClient(client *http.Client) *http.Client {
+	c := *client
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if transport, ok := base.(*http.Transport); ok {
+		transport = transport.Clone()
+		transport.DisableKeepAlives = true
+		c.Transport = transport
+	} else {
+		c.Transport = closingTransport{base: base}
+	}
+	return &c
+}
+
+// closingTransport 为每个请求设置 Connection: close，用于无法克隆的自定义 Transport
+type closingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip 发送请求并在响应后关闭连接
+func (t closingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Close = true
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,87 @@
+package tester
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newConnCountingServer 启动统计新建连接数的服务器
+func newConnCountingServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server, &conns
+}
+
+// TestParseConnMode 测试连接方式解析
+func TestParseConnMode(t *testing.T) {
+	for _, s := range []string{"", "pooled", "COLD", "both"} {
+		if _, err := ParseConnMode(s); err != nil {
+			t.Errorf("ParseConnMode(%q) error: %v", s, err)
+		}
+	}
+	if _, err := ParseConnMode("fresh"); err == nil {
+		t.Error("ParseConnMode(\"fresh\") should fail")
+	}
+}
+
+// TestTester_ConnMode 测试不同连接方式下的连接复用
+func TestTester_ConnMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       ConnMode
+		wantConns  int32
+		wantReused bool
+		wantWarm   bool
+	}{
+		// 3 次探测只建立一个连接，之后都复用
+		{"pooled", ConnPooled, 1, true, false},
+		// 每次探测都新建连接
+		{"cold", ConnCold, 3, false, false},
+		// 每次探测新建 1 个冷连接，热连接在连接池中只建立一次
+		{"both", ConnBoth, 4, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, conns := newConnCountingServer(t)
+			client := &http.Client{Transport: &http.Transport{}}
+			defer client.CloseIdleConnections()
+
+			tr := NewTester(client, 5*time.Second, WithConnMode(tt.mode))
+			var result TestResult
+			for i := 0; i < 3; i++ {
+				result = tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL})
+				if !result.Success {
+					t.Fatalf("TestSite() failed: %s", result.Error)
+				}
+			}
+
+			if got := atomic.LoadInt32(conns); got != tt.wantConns {
+				t.Errorf("connections = %d, want %d", got, tt.wantConns)
+			}
+			if result.Reused != tt.wantReused {
+				t.Errorf("Reused = %v, want %v", result.Reused, tt.wantReused)
+			}
+			if (result.WarmLatency > 0) != tt.wantWarm {
+				t.Errorf("WarmLatency = %v, want set = %v", result.WarmLatency, tt.wantWarm)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package tester
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/icarus-go/netspeed/pkg/proxy"
+)
+
+// ErrorCategory 失败原因分类
+type ErrorCategory string
+
+// 失败原因分类
+const (
+	ErrorNone       ErrorCategory = ""            // 成功或已取消
+	ErrorDNS        ErrorCategory = "dns"         // 域名解析失败
+	ErrorRefused    ErrorCategory = "refused"     // 连接被拒绝（端口未监听）
+	ErrorReset      ErrorCategory = "reset"       // 连接被重置或意外关闭（如握手后被 RST）
+	ErrorTLS        ErrorCategory = "tls"         // 证书或 TLS 协议错误
+	ErrorProxy      ErrorCategory = "proxy"       // 代理连接或认证失败
+	ErrorTimeout    ErrorCategory = "timeout"     // 超时
+	ErrorHTTPStatus ErrorCategory = "http-status" // 收到响应但状态码或内容不符合成功条件
+	ErrorUnknown    ErrorCategory = "unknown"     // 其他错误
+)
+
+// ErrorCategories 所有失败分类，按输出顺序排列
+var ErrorCategories = []ErrorCategory{
+	ErrorTimeout,
+	ErrorDNS,
+	ErrorRefused,
+	ErrorReset,
+	ErrorTLS,
+	ErrorProxy,
+	ErrorHTTPStatus,
+	ErrorUnknown,
+}
+
+// Label 返回分类的中文名称，用作失败结果的状态
+func (c ErrorCategory) Label() string {
+	switch c {
+	case ErrorDNS:
+		return "DNS失败"
+	case ErrorRefused:
+		return "连接拒绝"
+	case ErrorReset:
+		return "连接重置"
+	case ErrorTLS:
+		return "TLS错误"
+	case ErrorProxy:
+		return "代理错误"
+	case ErrorTimeout:
+		return "超时"
+	case ErrorHTTPStatus:
+		return "状态异常"
+	case ErrorNone:
+		return ""
+	default:
+		return "失败"
+	}
+}
+
+// errProxyAuth HTTP 代理要求认证（返回 407）
+var errProxyAuth = errors.New("代理需要认证: 407 Proxy Authentication Required")
+
+// Windows 套接字错误码（syscall 包中未全部定义，数值与 Unix errno 不冲突）
+const (
+	wsaECONNABORTED syscall.Errno = 10053
+	wsaECONNRESET   syscall.Errno = 10054
+	wsaECONNREFUSED syscall.Errno = 10061
+)
+
+// ClassifyError 根据错误链判断失败原因
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorNone
+	}
+
+	// DNS 错误（包括解析超时），以及限定地址族时域名没有该地址族的记录
+	var dnsErr *net.DNSError
+	var addrErr *net.AddrError
+	if errors.As(err, &dnsErr) || errors.As(err, &addrErr) {
+		return ErrorDNS
+	}
+
+	// 证书校验失败或 TLS 协议错误
+	if isTLSError(err) {
+		return ErrorTLS
+	}
+
+	if isTimeout(err) {
+		return ErrorTimeout
+	}
+
+	// 代理：HTTP 代理的 proxyconnect 和 SOCKS5 的 socks connect，以及 HTTP 代理拒绝连接或要求认证
+	// （CONNECT 被拒绝时的 *proxy.ConnectError 需使用 proxy.InitHTTPClient 创建的客户端）
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && (opErr.Op == "proxyconnect" || strings.HasPrefix(opErr.Op, "socks")) {
+		return ErrorProxy
+	}
+	var connectErr *proxy.ConnectError
+	if errors.Is(err, errProxyAuth) || errors.As(err, &connectErr) {
+		return ErrorProxy
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.ECONNREFUSED, wsaECONNREFUSED:
+			return ErrorRefused
+		case syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE, wsaECONNRESET, wsaECONNABORTED:
+			return ErrorReset
+		}
+	}
+
+	// 握手过程中对端直接关闭连接
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorReset
+	}
+
+	return ErrorUnknown
+}
+
+// isTLSError 判断是否为证书或 TLS 协议错误
+func isTLSError(err error) bool {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		unknownAuth  x509.UnknownAuthorityError
+		invalidCert  x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		echRejectErr *tls.ECHRejectionError
+	)
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &unknownAuth) ||
+		errors.As(err, &invalidCert) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &echRejectErr)
+}
+
+// isTimeout 判断错误是否为超时
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// failedResult 根据错误分类填充失败结果
+func failedResult(result TestResult, err error) TestResult {
+	result.Success = false
+	result.Error = err.Error()
+	result.Category = ClassifyError(err)
+	result.Status = result.Category.Label()
+	return result
+}
@@ -0,0 +1,149 @@
+package tester
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/icarus-go/netspeed/pkg/proxy"
+)
+
+// TestClassifyError 测试错误分类
+func TestClassifyError(t *testing.T) {
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com", Err: err}
+	}
+	syscallErr := func(errno syscall.Errno) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", errno)}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCategory
+	}{
+		{"nil", nil, ErrorNone},
+		{"DNS", wrap(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "x.invalid", IsNotFound: true}}), ErrorDNS},
+		{"DNS 超时", wrap(&net.DNSError{Err: "i/o timeout", IsTimeout: true}), ErrorDNS},
+		{"证书不受信任", wrap(x509.UnknownAuthorityError{}), ErrorTLS},
+		{"证书主机名不匹配", wrap(x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}}), ErrorTLS},
+		{"超时", wrap(context.DeadlineExceeded), ErrorTimeout},
+		{"HTTP 代理", wrap(&net.OpError{Op: "proxyconnect", Net: "tcp", Err: syscall.ECONNREFUSED}), ErrorProxy},
+		{"SOCKS5 代理", wrap(&net.OpError{Op: "socks connect", Net: "tcp", Err: errors.New("general SOCKS server failure")}), ErrorProxy},
+		{"HTTP 代理认证", wrap(&proxy.ConnectError{StatusCode: 407, Status: "407 Proxy Authentication Required"}), ErrorProxy},
+		{"与状态文本相同的其他错误", wrap(errors.New("Bad Gateway")), ErrorUnknown},
+		{"HTTP 代理 407 响应", errProxyAuth, ErrorProxy},
+		{"连接拒绝", wrap(syscallErr(syscall.ECONNREFUSED)), ErrorRefused},
+		{"连接拒绝 Windows", wrap(syscallErr(wsaECONNREFUSED)), ErrorRefused},
+		{"连接重置", wrap(&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), ErrorReset},
+		{"握手时被关闭", wrap(io.EOF), ErrorReset},
+		{"其他", wrap(errors.New("boom")), ErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_Category 测试探测失败时记录分类
+func TestTester_TestSite_Category(t *testing.T) {
+	// 接受连接后立即发送 RST
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*net.TCPConn).SetLinger(0)
+			conn.Close()
+		}
+	}()
+
+	// 已关闭的端口
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want ErrorCategory
+	}{
+		{"重置", fmt.Sprintf("http://%s", ln.Addr()), ErrorReset},
+		{"拒绝", fmt.Sprintf("http://%s", closedAddr), ErrorRefused},
+		{"TCP 拒绝", fmt.Sprintf("tcp://%s", closedAddr), ErrorRefused},
+		{"证书不受信任", tlsServer.URL, ErrorTLS},
+	}
+
+	tr := NewTester(&http.Client{}, 5*time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tr.TestSite(context.Background(), Site{Name: "local", URL: tt.url})
+			if result.Success {
+				t.Fatal("TestSite() should fail")
+			}
+			if result.Category != tt.want {
+				t.Errorf("Category = %q, want %q (error=%s)", result.Category, tt.want, result.Error)
+			}
+			if result.Status != tt.want.Label() {
+				t.Errorf("Status = %s, want %s", result.Status, tt.want.Label())
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_ProxyAuth 测试 HTTP 代理返回 407 时分类为代理错误
+func TestTester_TestSite_ProxyAuth(t *testing.T) {
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="proxy"`)
+		w.WriteHeader(http.StatusProxyAuthRequired)
+	}))
+	defer proxyServer.Close()
+
+	client, err := proxy.InitHTTPClient(proxyServer.URL, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTester(client, 5*time.Second)
+
+	// https 站点经由 CONNECT，http 站点由代理直接响应 407
+	for _, target := range []string{"https://example.invalid", "http://example.invalid"} {
+		t.Run(target, func(t *testing.T) {
+			result := tr.TestSite(context.Background(), Site{Name: "proxied", URL: target})
+			if result.Success {
+				t.Fatalf("TestSite() should fail, status=%s", result.Status)
+			}
+			if result.Category != ErrorProxy {
+				t.Errorf("Category = %q, want %q (error=%s)", result.Category, ErrorProxy, result.Error)
+			}
+			if result.Status != ErrorProxy.Label() {
+				t.Errorf("Status = %s, want %s", result.Status, ErrorProxy.Label())
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Family 探测使用的 IP 地址族
+type Family string
+
+// IP 地址族
+const (
+	FamilyAny  Family = ""     // 由系统决定（双栈时优先成功的一方）
+	Family4    Family = "4"    // 仅 IPv4
+	Family6    Family = "6"    // 仅 IPv6
+	FamilyBoth Family = "both" // 分别通过 IPv4 和 IPv6 探测
+)
+
+// ParseFamily 解析地址族参数
+func ParseFamily(s string) (Family, error) {
+	switch f := Family(s); f {
+	case FamilyAny, Family4, Family6, FamilyBoth:
+		return f, nil
+	default:
+		return "", fmt.Errorf("不支持的地址族: %s (支持 4, 6, both)", s)
+	}
+}
+
+// WithFamily 设置探测使用的 IP 地址族
+func WithFamily(f Family) Option {
+	return func(t *Tester) {
+		t.family = f
+	}
+}
+
+// network 返回地址族对应的拨号网络类型，FamilyAny 返回空字符串
+func (f Family) network() string {
+	switch f {
+	case Family4:
+		return "tcp4"
+	case Family6:
+		return "tcp6"
+	default:
+		return ""
+	}
+}
+
+// probeFamilies 返回需要准备探测实现的地址族
+func (f Family) probeFamilies() []Family {
+	if f == FamilyBoth {
+		return []Family{Family4, Family6}
+	}
+	return []Family{f}
+}
+
+// FamilyResult 通过单一地址族探测的结果
+type FamilyResult struct {
+	Success  bool
+	Latency  time.Duration
+	Addr     string        // 实际连接的服务器地址
+	Status   string        // 状态
+	Error    string        // 错误信息
+	Category ErrorCategory // 失败原因分类（dns 通常表示站点没有该地址族的记录）
+}
+
+// newFamilyResult 从完整探测结果中提取地址族结果
+func newFamilyResult(r TestResult) *FamilyResult {
+	return &FamilyResult{
+		Success:  r.Success,
+		Latency:  r.Latency,
+		Addr:     r.RemoteAddr,
+		Status:   r.Status,
+		Error:    r.Error,
+		Category: r.Category,
+	}
+}
+
+// probeDualStack 依次通过 IPv4 和 IPv6 探测站点
+// 返回 IPv4 的结果（IPv4 失败而 IPv6 成功时返回 IPv6 的结果），两个地址族的结果分别记录在 IPv4/IPv6 中
+func (t *Tester) probeDualStack(parent context.Context, site Site) TestResult {
+	v4 := t.probeFamily(parent, site, Family4)
+	v6 := t.probeFamily(parent, site, Family6)
+	if parent.Err() != nil {
+		return canceledResult(site)
+	}
+
+	result := v4
+	if !v4.Success && v6.Success {
+		result = v6
+	}
+	result.IPv4 = newFamilyResult(v4)
+	result.IPv6 = newFamilyResult(v6)
+	return result
+}
+
+// forceNetwork 将拨号函数的 tcp 网络类型替换为指定地址族
+func forceNetwork(dial DialContextFunc, network string) DialContextFunc {
+	return func(ctx context.Context, n, address string) (net.Conn, error) {
+		if n == "tcp" {
+			n = network
+		}
+		return dial(ctx, n, address)
+	}
+}
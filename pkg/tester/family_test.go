@@ -0,0 +1,98 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newFamilyServer 在指定回环地址上启动服务器，返回以 localhost 访问的 URL
+func newFamilyServer(t *testing.T, loopback string) string {
+	t.Helper()
+
+	// localhost 需要解析到该回环地址
+	addrs, _ := net.LookupHost("localhost")
+	if !slices.Contains(addrs, loopback) {
+		t.Skipf("localhost 未解析到 %s", loopback)
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(loopback, "0"))
+	if err != nil {
+		t.Skipf("无法监听 %s: %v", loopback, err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Listener = ln
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return fmt.Sprintf("http://localhost:%d", ln.Addr().(*net.TCPAddr).Port)
+}
+
+// TestParseFamily 测试地址族解析
+func TestParseFamily(t *testing.T) {
+	for _, s := range []string{"", "4", "6", "both"} {
+		if _, err := ParseFamily(s); err != nil {
+			t.Errorf("ParseFamily(%q) error: %v", s, err)
+		}
+	}
+	if _, err := ParseFamily("ipv6"); err == nil {
+		t.Error("ParseFamily(\"ipv6\") should fail")
+	}
+}
+
+// TestTester_Family_Both 测试双栈对比
+func TestTester_Family_Both(t *testing.T) {
+	tests := []struct {
+		name     string
+		loopback string
+		wantV4   bool
+		wantV6   bool
+		wantAddr string
+	}{
+		{"仅 IPv4", "127.0.0.1", true, false, "127.0.0.1"},
+		{"仅 IPv6", "::1", false, true, "::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := newFamilyServer(t, tt.loopback)
+
+			tr := NewTester(&http.Client{}, 5*time.Second, WithFamily(FamilyBoth))
+			result := tr.TestSite(context.Background(), Site{Name: "local", URL: url})
+
+			if !result.Success {
+				t.Fatalf("TestSite() failed: %s", result.Error)
+			}
+			if result.IPv4 == nil || result.IPv6 == nil {
+				t.Fatal("IPv4/IPv6 results should be set")
+			}
+			if result.IPv4.Success != tt.wantV4 || result.IPv6.Success != tt.wantV6 {
+				t.Errorf("IPv4.Success = %v, IPv6.Success = %v, want %v, %v",
+					result.IPv4.Success, result.IPv6.Success, tt.wantV4, tt.wantV6)
+			}
+			if result.RemoteAddr != tt.wantAddr {
+				t.Errorf("RemoteAddr = %q, want %q", result.RemoteAddr, tt.wantAddr)
+			}
+		})
+	}
+}
+
+// TestTester_Family_Single 测试限定单一地址族
+func TestTester_Family_Single(t *testing.T) {
+	url := newFamilyServer(t, "127.0.0.1")
+
+	tr := NewTester(&http.Client{}, 5*time.Second, WithFamily(Family4))
+	if result := tr.TestSite(context.Background(), Site{Name: "local", URL: url}); !result.Success {
+		t.Errorf("IPv4 TestSite() failed: %s", result.Error)
+	}
+
+	tr = NewTester(&http.Client{}, 5*time.Second, WithFamily(Family6))
+	if result := tr.TestSite(context.Background(), Site{Name: "local", URL: url}); result.Success {
+		t.Error("IPv6 TestSite() should fail for IPv4-only server")
+	}
+}
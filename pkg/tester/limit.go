@@ -0,0 +1,80 @@
+package tester
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// rateLimiter 按固定间隔放行请求的简单限速器
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter 创建每秒放行 rps 个请求的限速器，rps <= 0 时返回 nil（不限速）
+func newRateLimiter(rps float64) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+}
+
+// wait 阻塞直到允许发出下一批 n 个请求（n 个间隔计入限速），ctx 取消时返回错误
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(n) * l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// hostLocks 为每个主机提供一把互斥锁，使同一主机的站点串行测试
+type hostLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock 锁定站点所在主机，返回解锁函数
+func (h *hostLocks) lock(site Site) func() {
+	host := site.URL
+	if u, err := url.Parse(site.URL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	h.mu.Lock()
+	if h.locks == nil {
+		h.locks = make(map[string]*sync.Mutex)
+	}
+	l, ok := h.locks[host]
+	if !ok {
+		l = &sync.Mutex{}
+		h.locks[host] = l
+	}
+	h.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
@@ -0,0 +1,150 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyServer 记录同时处理中的最大请求数
+type concurrencyServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	total    int32
+}
+
+func newConcurrencyServer(delay time.Duration) *concurrencyServer {
+	s := &concurrencyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.total, 1)
+		s.mu.Lock()
+		s.inFlight++
+		if s.inFlight > s.peak {
+			s.peak = s.inFlight
+		}
+		s.mu.Unlock()
+
+		time.Sleep(delay)
+
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	return s
+}
+
+func (s *concurrencyServer) peakInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peak
+}
+
+// TestTester_TestAll_Concurrency 测试工作池限制并发数并保持结果顺序
+func TestTester_TestAll_Concurrency(t *testing.T) {
+	server := newConcurrencyServer(50 * time.Millisecond)
+	defer server.Close()
+
+	var sites []Site
+	for i := 0; i < 6; i++ {
+		sites = append(sites, Site{Name: string(rune('A' + i)), URL: server.URL})
+	}
+
+	tr := NewTester(server.Client(), 5*time.Second, WithConcurrency(2))
+	results := tr.TestAll(context.Background(), sites)
+
+	if got := server.peakInFlight(); got > 2 {
+		t.Errorf("peak in-flight = %d, want <= 2", got)
+	}
+	for i, result := range results {
+		if result.Name != sites[i].Name {
+			t.Errorf("results[%d].Name = %s, want %s", i, result.Name, sites[i].Name)
+		}
+		if !result.Success {
+			t.Errorf("results[%d] failed: %s", i, result.Error)
+		}
+	}
+}
+
+// TestTester_TestAll_PerHostSerial 测试同一主机的站点串行测试
+func TestTester_TestAll_PerHostSerial(t *testing.T) {
+	server := newConcurrencyServer(30 * time.Millisecond)
+	defer server.Close()
+
+	sites := []Site{
+		{Name: "A", URL: server.URL},
+		{Name: "B", URL: server.URL},
+		{Name: "C", URL: server.URL},
+	}
+
+	tr := NewTester(server.Client(), 5*time.Second, WithPerHostSerial(true))
+	tr.TestAll(context.Background(), sites)
+
+	if got := server.peakInFlight(); got != 1 {
+		t.Errorf("peak in-flight = %d, want 1", got)
+	}
+}
+
+// TestTester_TestAll_RateLimit 测试请求限速
+func TestTester_TestAll_RateLimit(t *testing.T) {
+	server := newConcurrencyServer(0)
+	defer server.Close()
+
+	sites := make([]Site, 5)
+	for i := range sites {
+		sites[i] = Site{Name: "local", URL: server.URL}
+	}
+
+	// 每秒 20 个请求：5 个请求至少间隔 4 × 50ms
+	tr := NewTester(server.Client(), 5*time.Second, WithRateLimit(20))
+	start := time.Now()
+	tr.TestAll(context.Background(), sites)
+
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("elapsed = %v, want >= 200ms", elapsed)
+	}
+	if got := atomic.LoadInt32(&server.total); got != 5 {
+		t.Errorf("requests = %d, want 5", got)
+	}
+}
+
+// TestTester_RateLimit_Connections 测试限速按建立的连接计数：-family both 每个地址族、-conn both 的冷/热连接各计一次
+func TestTester_RateLimit_Connections(t *testing.T) {
+	server := newConcurrencyServer(0)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		tokens int
+	}{
+		{"pooled", nil, 1},
+		{"conn both", []Option{WithConnMode(ConnBoth)}, 2},
+		{"family both", []Option{WithFamily(FamilyBoth)}, 2},
+		{"conn both + family both", []Option{WithConnMode(ConnBoth), WithFamily(FamilyBoth)}, 4},
+	}
+
+	const interval = 100 * time.Millisecond
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTester(server.Client(), 5*time.Second, append(tt.opts, WithRateLimit(10))...)
+			start := time.Now()
+			tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL})
+
+			// 每个连接占用一个间隔，下一次可发出请求的时间反映已计入的连接数
+			tr.limiter.mu.Lock()
+			reserved := tr.limiter.next.Sub(start)
+			tr.limiter.mu.Unlock()
+			want := time.Duration(tt.tokens) * interval
+			if reserved < want || reserved >= want+interval {
+				t.Errorf("reserved = %v, want %d connections (%v)", reserved, tt.tokens, want)
+			}
+		})
+	}
+}
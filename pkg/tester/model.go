@@ -6,45 +6,161 @@ import "time"
 type Site struct {
 	Name string `json:"Name"`
 	URL  string `json:"URL"`
+
+	// 分组与标签（可选），用于 -tags/-exclude-tags 筛选和按分组统计
+	Group string   `json:"Group,omitempty"`
+	Tags  []string `json:"Tags,omitempty"`
+
+	// DownloadURL 下载测速使用的大文件地址（可选）
+	DownloadURL string `json:"DownloadURL,omitempty"`
+
+	// UploadURL 上传测速接收 POST 数据的地址（可选）
+	UploadURL string `json:"UploadURL,omitempty"`
+
+	// 自定义请求（可选）：未配置时请求 HEAD /favicon.ico，失败后降级为 GET 首页
+	Method   string            `json:"Method,omitempty"`   // 请求方法，默认 GET，配置了请求体时默认 POST
+	Path     string            `json:"Path,omitempty"`     // 请求路径，拼接在 URL 之后
+	Headers  map[string]string `json:"Headers,omitempty"`  // 请求头，值中的 ${ENV} 引用环境变量（其他 $ 原样保留），Host 会覆盖请求主机名
+	Body     string            `json:"Body,omitempty"`     // 请求体
+	BodyFile string            `json:"BodyFile,omitempty"` // 从文件读取请求体，优先于 Body（配置文件中的相对路径相对于配置文件所在目录）
+	Auth     *Auth             `json:"Auth,omitempty"`     // 认证（basic / bearer）
+
+	// 成功条件（可选）：配置后直接请求站点地址而不是 favicon，需要校验响应体时使用 GET
+	ExpectStatus  []string          `json:"ExpectStatus,omitempty"`  // 期望状态码，如 "200"、"200-299"、"2xx"
+	BodyContains  string            `json:"BodyContains,omitempty"`  // 响应体需包含的子串
+	BodyRegex     string            `json:"BodyRegex,omitempty"`     // 响应体需匹配的正则
+	ExpectHeaders map[string]string `json:"ExpectHeaders,omitempty"` // 必须存在的响应头，值非空时要求包含该子串
+
+	// 重定向（可选）
+	Redirect      string   `json:"Redirect,omitempty"`      // 重定向策略: follow / none / 最大跳数，为空时使用全局策略
+	RedirectHosts []string `json:"RedirectHosts,omitempty"` // 允许跳转的其他域名（含子域名），其余跨域跳转标记为降级
+
+	// 超时与重试（可选）
+	Timeout float64 `json:"Timeout,omitempty"` // 单次探测超时（秒），为 0 时使用全局 -timeout
+	Retries *int    `json:"Retries,omitempty"` // 失败后最多重试的次数，为空时使用全局重试策略
+
+	// Thresholds 延迟评级阈值（可选），未配置的字段使用全局阈值
+	Thresholds *Thresholds `json:"Thresholds,omitempty"`
+
+	// ExpectIssuer 期望的证书签发者（匹配证书链中任一证书的主体），不符时标记为降级，用于发现中间人代理
+	ExpectIssuer string `json:"ExpectIssuer,omitempty"`
 }
 
 // TestResult 测试结果
 type TestResult struct {
-	Name    string
-	URL     string
-	Latency time.Duration
-	Status  string
-	Success bool
-	Error   string
+	Name       string
+	URL        string
+	StatusCode int // HTTP 状态码
+	Latency    time.Duration
+	Timing     Timing
+	Stats      LatencyStats // 多次采样统计（仅在采样次数大于 1 时填充）
+	Status     string
+	Success    bool
+	Error      string
+
+	// Level 评级（成功时按站点的评级阈值计算，Status 为其中文名称或"降级"）
+	Level Level
+
+	// Category 失败原因分类（成功时为空）
+	Category ErrorCategory
+
+	// RemoteAddr 实际连接的服务器地址（不含端口）
+	RemoteAddr string
+
+	// IPv4/IPv6 分别通过两个地址族探测的结果（仅 both 地址族模式填充）
+	IPv4 *FamilyResult
+	IPv6 *FamilyResult
+
+	// Attempts 探测次数（含重试），多次采样时为所有采样的总次数
+	Attempts int
+
+	// Reused 本次探测是否复用了已有连接（复用时 DNS/连接/TLS 耗时为 0）
+	Reused bool
+
+	// WarmLatency 复用连接时的延迟（仅 both 连接模式填充，Latency 为新建连接的延迟）
+	WarmLatency time.Duration
+
+	// Redirects 重定向链中的每一跳（含首个请求，未发生重定向时为空）
+	Redirects []RedirectHop
+
+	// Degraded 探测成功但存在问题（如证书即将过期），原因见 Warnings
+	Degraded bool
+	Warnings []string
+
+	// Cert TLS 连接与证书信息（仅 HTTPS 和 TLS 探测时填充）
+	Cert *CertInfo
+
+	// FailedAssertion 未通过的成功条件（为空表示全部通过或未配置）
+	FailedAssertion string
+
+	// Throughput 吞吐测试结果（仅带宽测试时填充）
+	Throughput *Throughput
+
+	// Bufferbloat 负载下延迟测试结果（仅负载下延迟测试时填充）
+	Bufferbloat *Bufferbloat
+
+	// PageLoad 页面加载测试结果（仅页面加载测试时填充）
+	PageLoad *PageLoad
 }
 
-// GetStatusByLatency 根据延迟判断状态
-func GetStatusByLatency(latency time.Duration) string {
-	ms := latency.Milliseconds()
-	switch {
-	case ms < 200:
-		return "优秀"
-	case ms < 500:
-		return "良好"
-	case ms < 1000:
-		return "一般"
-	default:
-		return "较差"
+// Retries 返回探测失败后重试的次数
+func (r TestResult) Retries() int {
+	samples := r.Stats.Samples
+	if samples < 1 {
+		samples = 1
+	}
+	if r.Attempts <= samples {
+		return 0
 	}
+	return r.Attempts - samples
+}
+
+// Canceled 测试是否在完成前被取消（失败的结果都有分类，只有取消的结果没有）
+func (r TestResult) Canceled() bool {
+	return !r.Success && r.Category == ErrorNone
+}
+
+// degrade 将成功的结果标记为降级并记录原因
+func (r *TestResult) degrade(warning string) {
+	r.Degraded = true
+	r.Warnings = append(r.Warnings, warning)
+	r.Status = "降级"
+}
+
+// Timing 请求各阶段耗时（连接复用时 DNS/Connect/TLS 为 0）
+type Timing struct {
+	DNS     time.Duration // DNS 解析
+	Connect time.Duration // TCP 连接
+	TLS     time.Duration // TLS 握手
+	TTFB    time.Duration // 首字节时间（从发出请求到收到响应首字节）
+	Total   time.Duration // 总耗时
+}
+
+// GetStatusByLatency 按默认阈值根据延迟判断状态
+func GetStatusByLatency(latency time.Duration) string {
+	return DefaultThresholds.Rate(latency).String()
 }
 
 // DefaultSites 默认测试网站列表
 var DefaultSites = []Site{
-	{"Google", "https://www.google.com"},
-	{"GitHub", "https://github.com"},
-	{"YouTube", "https://www.youtube.com"},
-	{"Twitter", "https://twitter.com"},
-	{"Facebook", "https://www.facebook.com"},
-	{"Instagram", "https://www.instagram.com"},
-	{"Reddit", "https://www.reddit.com"},
-	{"Netflix", "https://www.netflix.com"},
-	{"Wikipedia", "https://www.wikipedia.org"},
-	{"Amazon", "https://www.amazon.com"},
-	{"OpenAI", "https://www.openai.com"},
-	{"Telegram", "https://telegram.org"},
+	{Name: "Google", URL: "https://www.google.com"},
+	{Name: "GitHub", URL: "https://github.com"},
+	{Name: "YouTube", URL: "https://www.youtube.com"},
+	{Name: "Twitter", URL: "https://twitter.com"},
+	{Name: "Facebook", URL: "https://www.facebook.com"},
+	{Name: "Instagram", URL: "https://www.instagram.com"},
+	{Name: "Reddit", URL: "https://www.reddit.com"},
+	{Name: "Netflix", URL: "https://www.netflix.com"},
+	{Name: "Wikipedia", URL: "https://www.wikipedia.org"},
+	{Name: "Amazon", URL: "https://www.amazon.com"},
+	{Name: "OpenAI", URL: "https://www.openai.com"},
+	{Name: "Telegram", URL: "https://telegram.org"},
+}
+
+// DefaultBandwidthSites 默认带宽测试站点列表（配置文件中没有 DownloadURL/UploadURL 时使用）
+var DefaultBandwidthSites = []Site{
+	{Name: "Cloudflare", URL: "https://speed.cloudflare.com", DownloadURL: "https://speed.cloudflare.com/__down?bytes=1000000000", UploadURL: "https://speed.cloudflare.com/__up"},
+	{Name: "Hetzner", URL: "https://fsn1-speed.hetzner.com", DownloadURL: "https://fsn1-speed.hetzner.com/1GB.bin"},
+	{Name: "OVH", URL: "https://proof.ovh.net", DownloadURL: "https://proof.ovh.net/files/1Gb.dat"},
+	{Name: "Tele2", URL: "http://speedtest.tele2.net", DownloadURL: "http://speedtest.tele2.net/1GB.zip", UploadURL: "http://speedtest.tele2.net/upload.php"},
 }
@@ -0,0 +1,310 @@
+package tester
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// 页面加载读取的数据量上限
+const (
+	maxPageBody     = 5 << 20  // HTML 文档
+	maxResourceBody = 20 << 20 // 单个子资源
+)
+
+// 子资源类型
+const (
+	ResourceScript = "script"
+	ResourceStyle  = "style"
+	ResourceImage  = "image"
+)
+
+// PageLoadOptions 页面加载测试参数
+type PageLoadOptions struct {
+	MaxResources int // 最多加载的子资源数
+	PerHost      int // 每个主机的最大并行连接数（浏览器通常为 6）
+}
+
+// DefaultPageLoadOptions 默认页面加载测试参数
+var DefaultPageLoadOptions = PageLoadOptions{
+	MaxResources: 50,
+	PerHost:      6,
+}
+
+// DefaultPageLoadThresholds 页面加载总耗时的默认评级阈值（毫秒）
+var DefaultPageLoadThresholds = Thresholds{Excellent: 1000, Good: 3000, Fair: 6000}
+
+// PageLoad 页面加载测试结果
+type PageLoad struct {
+	Document  time.Duration // HTML 文档耗时
+	Total     time.Duration // 文档与所有子资源加载完成的总耗时
+	Bytes     int64         // 文档与子资源的总字节数
+	Hosts     int           // 连接的主机数（含文档所在主机）
+	Resources []Resource    // 子资源，按发现顺序
+	Slowest   *Resource     // 耗时最长的子资源
+}
+
+// Resource 页面中的一个子资源
+type Resource struct {
+	URL        string
+	Host       string
+	Kind       string // script / style / image
+	ThirdParty bool   // 是否为第三方域名
+	StatusCode int
+	Latency    time.Duration
+	Bytes      int64
+	Error      string
+	Category   ErrorCategory
+}
+
+// Failed 子资源是否加载失败
+func (r Resource) Failed() bool {
+	return r.Error != ""
+}
+
+// FailedHosts 返回有子资源加载失败的主机，按名称排序
+func (p *PageLoad) FailedHosts() []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, r := range p.Resources {
+		if r.Failed() && !seen[r.Host] {
+			seen[r.Host] = true
+			hosts = append(hosts, r.Host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// TestPageLoad 页面加载测试：GET 页面 HTML，解析出脚本、样式表和图片，
+// 按浏览器的方式（每个主机有限的并行连接）加载，记录总耗时、连接的主机数和最慢的资源
+func (t *Tester) TestPageLoad(ctx context.Context, site Site, opts PageLoadOptions) TestResult {
+	if opts.MaxResources <= 0 {
+		opts.MaxResources = DefaultPageLoadOptions.MaxResources
+	}
+	if opts.PerHost < 1 {
+		opts.PerHost = DefaultPageLoadOptions.PerHost
+	}
+
+	result := TestResult{
+		Name:    site.Name,
+		URL:     site.URL,
+		Success: false,
+	}
+
+	target := site.URL + site.Path
+	timeout := site.probeTimeout(t.timeout)
+	client := t.streamingClient()
+	start := time.Now()
+
+	// HTML 文档
+	docCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := newRequest(docCtx, site, http.MethodGet, target)
+	if err != nil {
+		return invalidRequest(result, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return canceledResult(site)
+		}
+		return failedResult(result, err)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBody))
+	resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+	if err != nil {
+		return failedResult(result, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.Error = fmt.Sprintf("服务器返回错误: %d", resp.StatusCode)
+		result.Category = ErrorHTTPStatus
+		result.Status = ErrorHTTPStatus.Label()
+		return result
+	}
+
+	page := &PageLoad{Document: time.Since(start), Bytes: int64(len(body))}
+	resources := parseResources(resp.Request.URL, body, opts.MaxResources)
+	page.Resources = t.fetchResources(ctx, client, resources, opts.PerHost, timeout)
+	if ctx.Err() != nil {
+		return canceledResult(site)
+	}
+	page.Total = time.Since(start)
+
+	hosts := map[string]bool{resp.Request.URL.Host: true}
+	for i := range page.Resources {
+		r := &page.Resources[i]
+		hosts[r.Host] = true
+		page.Bytes += r.Bytes
+		if page.Slowest == nil || r.Latency > page.Slowest.Latency {
+			page.Slowest = r
+		}
+	}
+	page.Hosts = len(hosts)
+
+	result.Success = true
+	result.Latency = page.Total
+	result.PageLoad = page
+
+	th := DefaultPageLoadThresholds
+	if site.Thresholds != nil {
+		th = th.Merge(*site.Thresholds)
+	}
+	result.Level = th.Rate(page.Total)
+	result.Status = result.Level.String()
+	if failed := page.FailedHosts(); len(failed) > 0 {
+		result.degrade(fmt.Sprintf("子资源加载失败的主机: %s", strings.Join(failed, ", ")))
+	}
+	return result
+}
+
+// parseResources 从 HTML 中解析脚本、样式表和图片地址，相对地址按页面地址（或 <base>）解析，去重后最多返回 limit 个
+func parseResources(page *url.URL, body []byte, limit int) []Resource {
+	base := page
+	seen := make(map[string]bool)
+	var resources []Resource
+
+	add := func(ref, kind string) {
+		ref = strings.TrimSpace(ref)
+		if ref == "" || strings.HasPrefix(ref, "data:") || len(resources) >= limit {
+			return
+		}
+		u, err := base.Parse(ref)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		u.Fragment = ""
+		if seen[u.String()] {
+			return
+		}
+		seen[u.String()] = true
+		resources = append(resources, Resource{
+			URL:        u.String(),
+			Host:       u.Host,
+			Kind:       kind,
+			ThirdParty: !sameSite(strings.ToLower(page.Hostname()), strings.ToLower(u.Hostname())),
+		})
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return resources
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tag := z.Token()
+		attrs := make(map[string]string, len(tag.Attr))
+		for _, a := range tag.Attr {
+			attrs[strings.ToLower(a.Key)] = a.Val
+		}
+
+		switch tag.Data {
+		case "base":
+			if u, err := page.Parse(attrs["href"]); err == nil && attrs["href"] != "" {
+				base = u
+			}
+		case "script":
+			add(attrs["src"], ResourceScript)
+		case "link":
+			if hasToken(attrs["rel"], "stylesheet") {
+				add(attrs["href"], ResourceStyle)
+			}
+		case "img":
+			add(attrs["src"], ResourceImage)
+		}
+	}
+}
+
+// hasToken 判断空格分隔的属性值中是否包含 token（不区分大小写）
+func hasToken(value, token string) bool {
+	for _, f := range strings.Fields(value) {
+		if strings.EqualFold(f, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchResources 并行加载子资源，每个主机最多 perHost 个并行请求
+func (t *Tester) fetchResources(ctx context.Context, client *http.Client, resources []Resource, perHost int, timeout time.Duration) []Resource {
+	var mu sync.Mutex
+	slots := make(map[string]chan struct{})
+	slot := func(host string) chan struct{} {
+		mu.Lock()
+		defer mu.Unlock()
+		if slots[host] == nil {
+			slots[host] = make(chan struct{}, perHost)
+		}
+		return slots[host]
+	}
+
+	var wg sync.WaitGroup
+	for i := range resources {
+		wg.Add(1)
+		go func(r *Resource) {
+			defer wg.Done()
+
+			sem := slot(r.Host)
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				r.Error = ctx.Err().Error()
+				return
+			}
+			defer func() { <-sem }()
+
+			fetchResource(ctx, client, r, timeout)
+		}(&resources[i])
+	}
+	wg.Wait()
+	return resources
+}
+
+// fetchResource 加载单个子资源并读取完整响应体
+func fetchResource(parent context.Context, client *http.Client, r *Resource, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { r.Latency = time.Since(start) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		r.Error = err.Error()
+		r.Category = ErrorUnknown
+		return
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		r.Error = err.Error()
+		r.Category = ClassifyError(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	r.StatusCode = resp.StatusCode
+	r.Bytes, err = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResourceBody))
+	switch {
+	case err != nil:
+		r.Error = err.Error()
+		r.Category = ClassifyError(err)
+	case resp.StatusCode >= 400:
+		r.Error = fmt.Sprintf("服务器返回错误: %d", resp.StatusCode)
+		r.Category = ErrorHTTPStatus
+	}
+}
@@ -0,0 +1,135 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseResources 测试从 HTML 中解析子资源
+func TestParseResources(t *testing.T) {
+	page, _ := url.Parse("https://www.example.com/news/index.html")
+	body := []byte(`<html><head>
+		<link rel="stylesheet" href="/css/site.css">
+		<link rel="icon" href="/favicon.ico">
+		<script src="app.js"></script>
+		<script>inline()</script>
+		<script src="https://cdn.example-cdn.net/lib.js"></script>
+	</head><body>
+		<img src="https://static.example.com/logo.png">
+		<img src="data:image/png;base64,AAAA">
+		<img src="/css/site.css#dup">
+		<IMG SRC="/img/a.png"/>
+	</body></html>`)
+
+	got := parseResources(page, body, 10)
+	want := []struct {
+		url        string
+		kind       string
+		thirdParty bool
+	}{
+		{"https://www.example.com/css/site.css", ResourceStyle, false},
+		{"https://www.example.com/news/app.js", ResourceScript, false},
+		{"https://cdn.example-cdn.net/lib.js", ResourceScript, true},
+		{"https://static.example.com/logo.png", ResourceImage, false},
+		{"https://www.example.com/img/a.png", ResourceImage, false},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseResources() = %+v, want %d resources", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].URL != w.url || got[i].Kind != w.kind || got[i].ThirdParty != w.thirdParty {
+			t.Errorf("resources[%d] = %+v, want %+v", i, got[i], w)
+		}
+	}
+
+	if limited := parseResources(page, body, 2); len(limited) != 2 {
+		t.Errorf("len(parseResources(limit 2)) = %d, want 2", len(limited))
+	}
+}
+
+// TestTester_TestPageLoad 测试页面加载：子资源并行加载、每主机连接数限制和失败的第三方主机
+func TestTester_TestPageLoad(t *testing.T) {
+	// 一个已关闭的端口，模拟被屏蔽的第三方主机
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocked := "http://localhost:" + strings.Split(ln.Addr().String(), ":")[1]
+	ln.Close()
+
+	var inflight, maxInflight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			var b strings.Builder
+			b.WriteString(`<html><head><link rel="stylesheet" href="/site.css"></head><body>`)
+			for i := 0; i < 8; i++ {
+				fmt.Fprintf(&b, `<img src="/img/%d.png">`, i)
+			}
+			fmt.Fprintf(&b, `<script src="%s/tracker.js"></script></body></html>`, blocked)
+			w.Write([]byte(b.String()))
+			return
+		}
+
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			m := maxInflight.Load()
+			if n <= m || maxInflight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		delay := 20 * time.Millisecond
+		if r.URL.Path == "/img/7.png" {
+			delay = 100 * time.Millisecond
+		}
+		time.Sleep(delay)
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 2*time.Second)
+	result := tr.TestPageLoad(context.Background(), Site{Name: "local", URL: server.URL}, PageLoadOptions{PerHost: 3})
+
+	if !result.Success {
+		t.Fatalf("TestPageLoad() failed: %s", result.Error)
+	}
+	page := result.PageLoad
+	if len(page.Resources) != 10 {
+		t.Fatalf("len(Resources) = %d, want 10", len(page.Resources))
+	}
+	if page.Hosts != 2 {
+		t.Errorf("Hosts = %d, want 2", page.Hosts)
+	}
+	if got := maxInflight.Load(); got > 3 {
+		t.Errorf("max parallel requests per host = %d, want <= 3", got)
+	}
+	if page.Total < page.Document || page.Total < 100*time.Millisecond {
+		t.Errorf("Total = %v, Document = %v", page.Total, page.Document)
+	}
+
+	if page.Slowest == nil || !strings.HasSuffix(page.Slowest.URL, "/img/7.png") {
+		t.Errorf("Slowest = %+v, want /img/7.png", page.Slowest)
+	}
+
+	if failed := page.FailedHosts(); len(failed) != 1 || !strings.HasPrefix(failed[0], "localhost:") {
+		t.Errorf("FailedHosts() = %v, want the blocked host", failed)
+	}
+	if !result.Degraded {
+		t.Errorf("Degraded = false, want true when a third-party host fails")
+	}
+	for _, r := range page.Resources {
+		if r.Failed() && (!r.ThirdParty || r.Category != ErrorRefused) {
+			t.Errorf("failed resource %+v, want third-party refused", r)
+		}
+	}
+}
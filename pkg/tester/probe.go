@@ -0,0 +1,157 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/icarus-go/netspeed/pkg/proxy"
+)
+
+// Probe 一种协议的探测实现
+// ctx 已带单次探测的超时，实现只需完成一次探测并返回统一的 TestResult
+type Probe interface {
+	Probe(ctx context.Context, site Site) TestResult
+}
+
+// DialContextFunc 建立网络连接的函数，与 http.Transport.DialContext 签名一致
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// 支持的探测协议
+const (
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+	SchemeTCP   = "tcp"
+	SchemeTLS   = "tls"
+	SchemeDNS   = "dns"
+)
+
+// Schemes 内置探测支持的全部协议
+var Schemes = []string{SchemeHTTP, SchemeHTTPS, SchemeTCP, SchemeTLS, SchemeDNS}
+
+// WithProbe 注册或替换某个协议的探测实现（对所有地址族生效）
+func WithProbe(scheme string, p Probe) Option {
+	return func(t *Tester) {
+		if t.custom == nil {
+			t.custom = make(map[string]Probe)
+		}
+		t.custom[strings.ToLower(scheme)] = p
+	}
+}
+
+// WithDialContext 设置 TCP/TLS/DNS 探测建立连接的方式（如经由 SOCKS5 代理）
+// HTTP 探测仍使用 http.Client 自身的 Transport
+func WithDialContext(dial DialContextFunc) Option {
+	return func(t *Tester) {
+		t.dial = dial
+	}
+}
+
+// registerDefaultProbes 为需要测试的每个地址族注册内置探测实现
+func (t *Tester) registerDefaultProbes() {
+	t.probes = make(map[Family]map[string]Probe)
+	for _, family := range t.family.probeFamilies() {
+		t.probes[family] = t.defaultProbes(family)
+	}
+}
+
+// defaultProbes 创建使用指定地址族的内置探测实现
+func (t *Tester) defaultProbes(family Family) map[string]Probe {
+	dial := t.dial
+	dnsNetwork := ""
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	} else {
+		// 代理通常只支持 TCP，DNS 查询改走 TCP
+		dnsNetwork = "tcp"
+	}
+
+	// DNS 探测连接的是解析器，不受地址族限制
+	dnsDial := dial
+	client := t.client
+	if network := family.network(); network != "" {
+		client = proxy.ForceFamily(client, network)
+		dial = forceNetwork(dial, network)
+	}
+
+	httpProbe := newHTTPProbe(client, t.connMode, t.certWarnDays, t.maxRedirects)
+	return map[string]Probe{
+		SchemeHTTP:  httpProbe,
+		SchemeHTTPS: httpProbe,
+		SchemeTCP:   &tcpProbe{dial: dial},
+		SchemeTLS:   &tlsProbe{dial: dial, config: clientTLSConfig(t.client), certWarnDays: t.certWarnDays},
+		SchemeDNS:   &dnsProbe{dial: dnsDial, network: dnsNetwork},
+	}
+}
+
+// probeFor 根据站点 URL 的协议选择探测实现，通过 WithProbe 注册的实现优先
+func (t *Tester) probeFor(site Site, family Family) (Probe, error) {
+	u, err := url.Parse(site.URL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 URL: %v", err)
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("URL 缺少协议: %s", site.URL)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if p, ok := t.custom[scheme]; ok {
+		return p, nil
+	}
+	p, ok := t.probes[family][scheme]
+	if !ok {
+		return nil, fmt.Errorf("不支持的协议: %s", u.Scheme)
+	}
+	return p, nil
+}
+
+// probeResult 根据探测耗时和错误生成 TestResult（成功结果由 Tester 按评级阈值评级）
+func probeResult(site Site, timing Timing, err error) TestResult {
+	result := TestResult{
+		Name:    site.Name,
+		URL:     site.URL,
+		Success: false,
+	}
+
+	if err != nil {
+		return failedResult(result, err)
+	}
+
+	result.Latency = timing.Total
+	result.Timing = timing
+	result.Success = true
+	return result
+}
+
+// errorResult 返回配置错误导致无法探测的结果
+func errorResult(site Site, err error) TestResult {
+	return TestResult{
+		Name:     site.Name,
+		URL:      site.URL,
+		Status:   "错误",
+		Error:    err.Error(),
+		Category: ErrorUnknown,
+	}
+}
+
+// hostPort 从 URL 中取出 host:port，未指定端口时使用 defaultPort（为空表示必须指定）
+func hostPort(rawURL, defaultPort string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("无效的 URL: %v", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("URL 缺少主机名: %s", rawURL)
+	}
+
+	port := u.Port()
+	if port == "" {
+		if defaultPort == "" {
+			return "", fmt.Errorf("URL 缺少端口: %s", rawURL)
+		}
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
@@ -0,0 +1,70 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// dnsProbe DNS 解析探测，URL 形如 dns://resolver[:port]/name[?type=A|AAAA]
+// resolver 为空（dns:///name）时使用系统解析器
+type dnsProbe struct {
+	dial    DialContextFunc
+	network string // 非空时强制使用该网络类型（经由代理时为 tcp）
+}
+
+// Probe 解析一次域名，以解析耗时作为延迟
+func (p *dnsProbe) Probe(ctx context.Context, site Site) TestResult {
+	u, err := url.Parse(site.URL)
+	if err != nil {
+		return errorResult(site, fmt.Errorf("无效的 URL: %v", err))
+	}
+
+	name := strings.TrimPrefix(u.Path, "/")
+	if name == "" {
+		return errorResult(site, fmt.Errorf("URL 缺少要解析的域名: %s", site.URL))
+	}
+
+	network := "ip"
+	switch strings.ToUpper(u.Query().Get("type")) {
+	case "", "ANY":
+	case "A":
+		network = "ip4"
+	case "AAAA":
+		network = "ip6"
+	default:
+		return errorResult(site, fmt.Errorf("不支持的记录类型: %s", u.Query().Get("type")))
+	}
+
+	resolver := net.DefaultResolver
+	if u.Host != "" {
+		server := u.Host
+		if u.Port() == "" {
+			server = net.JoinHostPort(u.Hostname(), "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, n, _ string) (net.Conn, error) {
+				if p.network != "" {
+					n = p.network
+				}
+				return p.dial(ctx, n, server)
+			},
+		}
+	}
+
+	start := time.Now()
+	ips, err := resolver.LookupIP(ctx, network, name)
+	latency := time.Since(start)
+	if err == nil && len(ips) == 0 {
+		err = fmt.Errorf("没有解析到 %s 的地址", name)
+	}
+	if err != nil {
+		return probeResult(site, Timing{}, err)
+	}
+
+	return probeResult(site, Timing{DNS: latency, Total: latency}, nil)
+}
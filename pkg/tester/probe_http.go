@@ -0,0 +1,208 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// httpProbe HTTP/HTTPS 探测
+// 默认请求 HEAD /favicon.ico，失败后降级为 GET 首页；站点可自定义请求和成功条件
+type httpProbe struct {
+	client       *http.Client
+	cold         *http.Client // 禁用连接复用的客户端（cold/both 模式）
+	mode         ConnMode
+	certWarnDays int // 证书剩余天数低于该值时标记为降级，0 表示不检查
+	maxRedirects int // 默认最多跟随的重定向次数
+}
+
+// newHTTPProbe 创建 HTTP 探测，cold/both 模式下额外准备禁用连接复用的客户端
+// 重定向由探测逐跳跟随，客户端本身不再自动跟随
+// 超时由探测的 context 控制（站点可单独设置），不使用客户端的整体超时
+func newHTTPProbe(client *http.Client, mode ConnMode, certWarnDays, maxRedirects int) *httpProbe {
+	c := *client
+	c.Timeout = 0
+	client = &c
+
+	p := &httpProbe{
+		client:       noRedirectClient(client),
+		mode:         mode,
+		certWarnDays: certWarnDays,
+		maxRedirects: maxRedirects,
+	}
+	if mode == ConnCold || mode == ConnBoth {
+		p.cold = noRedirectClient(coldClient(client))
+	}
+	return p
+}
+
+// Probe 对站点进行一次 HTTP 探测
+func (p *httpProbe) Probe(ctx context.Context, site Site) TestResult {
+	switch p.mode {
+	case ConnCold:
+		return p.probe(ctx, site, p.cold)
+	case ConnBoth:
+		// 先用新连接测冷延迟，再在连接池中的连接上测热延迟
+		result := p.probe(ctx, site, p.cold)
+		if result.Success {
+			if warm := p.probeWarm(ctx, site); warm.Success && warm.Reused {
+				result.WarmLatency = warm.Latency
+			}
+		}
+		return result
+	default:
+		return p.probe(ctx, site, p.client)
+	}
+}
+
+// probeWarm 在连接池上探测，首次请求建立的是新连接时再请求一次以测量复用连接
+func (p *httpProbe) probeWarm(ctx context.Context, site Site) TestResult {
+	result := p.probe(ctx, site, p.client)
+	if result.Success && !result.Reused {
+		result = p.probe(ctx, site, p.client)
+	}
+	return result
+}
+
+// probe 使用指定客户端探测一次，HEAD 失败时降级为 GET
+func (p *httpProbe) probe(ctx context.Context, site Site, client *http.Client) TestResult {
+	method, target, fallback := site.requestPlan()
+	result, err := p.send(ctx, client, site, method, target)
+	if err == nil {
+		return result
+	}
+
+	if !fallback {
+		// 自定义请求或已经是 GET 请求，无需降级
+		return failedResult(result, err)
+	}
+
+	// 降级：尝试 GET 首页
+	return p.fallbackTest(site, ctx, client)
+}
+
+// fallbackTest 降级测试（使用 GET 请求）
+func (p *httpProbe) fallbackTest(site Site, ctx context.Context, client *http.Client) TestResult {
+	result, err := p.send(ctx, client, site, "GET", site.URL)
+	if err != nil {
+		return failedResult(result, err)
+	}
+	return result
+}
+
+// send 发送一次请求（按重定向策略逐跳跟随）并按成功条件校验最终响应
+// 只有请求发送失败（网络错误、超时等）时返回 error，由调用方决定是否降级
+func (p *httpProbe) send(ctx context.Context, client *http.Client, site Site, method, target string) (TestResult, error) {
+	result := TestResult{
+		Name:    site.Name,
+		URL:     site.URL,
+		Success: false,
+	}
+
+	maxRedirects, err := site.redirectLimit(p.maxRedirects)
+	if err != nil {
+		return invalidRequest(result, err), nil
+	}
+
+	var resp *http.Response
+	var tracer *phaseTracer
+	var unexpected []string
+	keepBody, sameHost := true, true
+	for hop := 0; ; hop++ {
+		// 每一跳单独挂载阶段追踪
+		var traceCtx context.Context
+		traceCtx, tracer = withPhaseTrace(ctx)
+		req, err := buildRequest(traceCtx, site, method, target, keepBody, sameHost)
+		if err != nil {
+			return invalidRequest(result, err), nil
+		}
+
+		// 记录开始时间
+		tracer.begin()
+		start := time.Now()
+
+		// 发送请求
+		resp, err = client.Do(req)
+		latency := time.Since(start)
+
+		if err != nil {
+			return result, err
+		}
+
+		result.Latency += latency
+		result.Timing = result.Timing.add(tracer.timing(latency))
+
+		step := RedirectHop{
+			URL:        req.URL.String(),
+			StatusCode: resp.StatusCode,
+			Latency:    latency,
+			Unexpected: hop > 0 && !site.expectedRedirect(req.URL),
+		}
+		if step.Unexpected {
+			unexpected = append(unexpected, req.URL.Hostname())
+		}
+		result.Redirects = append(result.Redirects, step)
+
+		next := redirectTarget(resp)
+		if next == nil || hop >= maxRedirects {
+			break
+		}
+
+		// 丢弃重定向响应体以便复用连接
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+
+		next = req.URL.ResolveReference(next)
+		var keep bool
+		method, keep = redirectMethod(resp.StatusCode, method)
+		keepBody = keepBody && keep
+		sameHost = sameHost && next.Host == req.URL.Host
+		target = next.String()
+	}
+	defer resp.Body.Close()
+
+	// 没有发生重定向时不记录
+	if len(result.Redirects) == 1 {
+		result.Redirects = nil
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.Reused = tracer.reused()
+	result.RemoteAddr = tracer.remote()
+	// 证书在校验成功条件之前记录，校验失败时同样可以查看
+	result.Cert = newCertInfo(resp.TLS)
+
+	// 407 来自代理而不是目标站点（http 站点经由 HTTP 代理时代理直接响应）
+	if resp.StatusCode == http.StatusProxyAuthRequired {
+		return failedResult(result, errProxyAuth), nil
+	}
+
+	if failed := checkResponse(site, resp); failed != "" {
+		result.FailedAssertion = failed
+		result.Error = failed
+		result.Category = ErrorHTTPStatus
+		result.Status = "校验失败"
+		return result, nil
+	}
+
+	result.Success = true
+	for _, host := range unexpected {
+		result.degrade(fmt.Sprintf("重定向到非预期的域名 %s", host))
+	}
+	if maxRedirects > 0 && redirectTarget(resp) != nil {
+		result.degrade(fmt.Sprintf("重定向超过 %d 次", maxRedirects))
+	}
+	result.checkCert(site, p.certWarnDays)
+
+	return result, nil
+}
+
+// invalidRequest 无法构造请求（配置错误）时的结果
+func invalidRequest(result TestResult, err error) TestResult {
+	result.Error = err.Error()
+	result.Status = "错误"
+	result.Category = ErrorUnknown
+	return result
+}
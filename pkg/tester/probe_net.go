@@ -0,0 +1,91 @@
+package tester
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// tcpProbe TCP 连接探测，URL 形如 tcp://host:port
+type tcpProbe struct {
+	dial DialContextFunc
+}
+
+// Probe 建立一次 TCP 连接，以连接耗时作为延迟
+func (p *tcpProbe) Probe(ctx context.Context, site Site) TestResult {
+	addr, err := hostPort(site.URL, "")
+	if err != nil {
+		return errorResult(site, err)
+	}
+
+	start := time.Now()
+	conn, err := p.dial(ctx, "tcp", addr)
+	if err != nil {
+		return probeResult(site, Timing{}, err)
+	}
+	connect := time.Since(start)
+	conn.Close()
+
+	result := probeResult(site, Timing{Connect: connect, Total: connect}, nil)
+	result.RemoteAddr = hostOf(conn.RemoteAddr())
+	return result
+}
+
+// tlsProbe TLS 握手探测，URL 形如 tls://host:port（默认端口 443）
+type tlsProbe struct {
+	dial         DialContextFunc
+	config       *tls.Config // 与 HTTP 客户端共用的 TLS 配置（如自定义根证书），可为 nil
+	certWarnDays int         // 证书剩余天数低于该值时标记为降级，0 表示不检查
+}
+
+// Probe 建立 TCP 连接并完成 TLS 握手
+func (p *tlsProbe) Probe(ctx context.Context, site Site) TestResult {
+	addr, err := hostPort(site.URL, "443")
+	if err != nil {
+		return errorResult(site, err)
+	}
+	host, _, _ := net.SplitHostPort(addr)
+
+	start := time.Now()
+	raw, err := p.dial(ctx, "tcp", addr)
+	if err != nil {
+		return probeResult(site, Timing{}, err)
+	}
+	defer raw.Close()
+	connect := time.Since(start)
+
+	config := &tls.Config{}
+	if p.config != nil {
+		config = p.config.Clone()
+	}
+	config.ServerName = host
+
+	conn := tls.Client(raw, config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return probeResult(site, Timing{}, err)
+	}
+	total := time.Since(start)
+
+	result := probeResult(site, Timing{
+		Connect: connect,
+		TLS:     total - connect,
+		Total:   total,
+	}, nil)
+	result.RemoteAddr = hostOf(raw.RemoteAddr())
+	state := conn.ConnectionState()
+	result.applyCert(site, &state, p.certWarnDays)
+	return result
+}
+
+// clientTLSConfig 取出 HTTP 客户端使用的 TLS 配置
+func clientTLSConfig(client *http.Client) *tls.Config {
+	if client == nil {
+		return nil
+	}
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		return transport.TLSClientConfig
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package tester
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestTester_TestSite_TCP 测试 TCP 探测
+func TestTester_TestSite_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// 经由自定义拨号函数（模拟 SOCKS5 代理）建立连接
+	var dials int32
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}
+
+	tr := NewTester(http.DefaultClient, 5*time.Second, WithDialContext(dial))
+	result := tr.TestSite(context.Background(), Site{Name: "tcp", URL: "tcp://" + ln.Addr().String()})
+
+	if !result.Success {
+		t.Fatalf("TestSite() failed: %s", result.Error)
+	}
+	if result.Timing.Connect <= 0 || result.Timing.Connect != result.Latency {
+		t.Errorf("Connect = %v, Latency = %v", result.Timing.Connect, result.Latency)
+	}
+	if atomic.LoadInt32(&dials) != 1 {
+		t.Errorf("dials = %d, want 1", dials)
+	}
+
+	// 端口未监听
+	ln.Close()
+	result = tr.TestSite(context.Background(), Site{Name: "tcp", URL: "tcp://" + ln.Addr().String()})
+	if result.Success {
+		t.Error("TestSite() should fail when port is closed")
+	}
+
+	// 缺少端口
+	result = tr.TestSite(context.Background(), Site{Name: "tcp", URL: "tcp://127.0.0.1"})
+	if result.Success || result.Status != "错误" {
+		t.Errorf("Status = %s, want 错误", result.Status)
+	}
+}
+
+// TestTester_TestSite_TLS 测试 TLS 握手探测
+func TestTester_TestSite_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	addr := strings.TrimPrefix(server.URL, "https://")
+	result := tr.TestSite(context.Background(), Site{Name: "tls", URL: "tls://" + addr})
+
+	if !result.Success {
+		t.Fatalf("TestSite() failed: %s", result.Error)
+	}
+	if result.Timing.TLS <= 0 {
+		t.Errorf("TLS = %v, want > 0", result.Timing.TLS)
+	}
+
+	// 不信任服务器证书时握手失败
+	tr = NewTester(http.DefaultClient, 5*time.Second)
+	result = tr.TestSite(context.Background(), Site{Name: "tls", URL: "tls://" + addr})
+	if result.Success {
+		t.Error("TestSite() should fail with untrusted certificate")
+	}
+}
+
+// TestTester_TestSite_DNS 测试 DNS 解析探测
+func TestTester_TestSite_DNS(t *testing.T) {
+	resolver := newDNSServer(t)
+
+	tr := NewTester(http.DefaultClient, 5*time.Second)
+
+	result := tr.TestSite(context.Background(), Site{Name: "dns", URL: "dns://" + resolver + "/example.test?type=A"})
+	if !result.Success {
+		t.Fatalf("TestSite() failed: %s", result.Error)
+	}
+	if result.Timing.DNS <= 0 {
+		t.Errorf("DNS = %v, want > 0", result.Timing.DNS)
+	}
+
+	result = tr.TestSite(context.Background(), Site{Name: "dns", URL: "dns://" + resolver + "/missing.test?type=A"})
+	if result.Success {
+		t.Error("TestSite() should fail for NXDOMAIN")
+	}
+
+	result = tr.TestSite(context.Background(), Site{Name: "dns", URL: "dns://" + resolver + "/"})
+	if result.Status != "错误" {
+		t.Errorf("Status = %s, want 错误", result.Status)
+	}
+}
+
+// TestTester_ProbeDispatch 测试按协议分发和自定义探测实现
+func TestTester_ProbeDispatch(t *testing.T) {
+	custom := probeFunc(func(ctx context.Context, site Site) TestResult {
+		return probeResult(site, Timing{Total: time.Millisecond}, nil)
+	})
+
+	tr := NewTester(http.DefaultClient, time.Second, WithProbe("ICMP", custom))
+	results := tr.TestAll(context.Background(), []Site{
+		{Name: "custom", URL: "icmp://127.0.0.1"},
+		{Name: "unknown", URL: "ftp://127.0.0.1"},
+		{Name: "no-scheme", URL: "127.0.0.1:22"},
+	})
+
+	if !results[0].Success {
+		t.Errorf("custom probe failed: %s", results[0].Error)
+	}
+	for _, r := range results[1:] {
+		if r.Success || r.Status != "错误" {
+			t.Errorf("%s: Status = %s, want 错误", r.Name, r.Status)
+		}
+	}
+}
+
+// probeFunc 用函数实现 Probe
+type probeFunc func(ctx context.Context, site Site) TestResult
+
+func (f probeFunc) Probe(ctx context.Context, site Site) TestResult {
+	return f(ctx, site)
+}
+
+// newDNSServer 启动只应答 example.test A 记录的 UDP DNS 服务器，返回监听地址
+func newDNSServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var parser dnsmessage.Parser
+			header, err := parser.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			question, err := parser.Question()
+			if err != nil {
+				continue
+			}
+
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: header.ID, Response: true, RecursionAvailable: true},
+				Questions: []dnsmessage.Question{question},
+			}
+			if question.Name.String() == "example.test." && question.Type == dnsmessage.TypeA {
+				resp.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: question.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+					Body:   &dnsmessage.AResource{A: [4]byte{127, 0, 0, 1}},
+				}}
+			} else {
+				resp.RCode = dnsmessage.RCodeNameError
+			}
+
+			packed, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
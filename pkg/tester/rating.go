@@ -0,0 +1,141 @@
+package tester
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Level 评级（逐行状态与整体网络质量共用）
+type Level int
+
+// 评级从好到差排列，LevelNone 表示未评级（如探测失败）
+const (
+	LevelNone Level = iota
+	LevelExcellent
+	LevelGood
+	LevelFair
+	LevelPoor
+)
+
+// String 返回评级的中文名称
+func (l Level) String() string {
+	switch l {
+	case LevelExcellent:
+		return "优秀"
+	case LevelGood:
+		return "良好"
+	case LevelFair:
+		return "一般"
+	case LevelPoor:
+		return "较差"
+	default:
+		return ""
+	}
+}
+
+// Thresholds 延迟评级阈值（毫秒）：低于 Excellent 为优秀，低于 Good 为良好，低于 Fair 为一般，否则为较差
+// 为 0 的字段沿用上一级配置（站点 → 全局 → 默认）
+type Thresholds struct {
+	Excellent int `json:"Excellent,omitempty"`
+	Good      int `json:"Good,omitempty"`
+	Fair      int `json:"Fair,omitempty"`
+}
+
+// DefaultThresholds 默认延迟评级阈值
+var DefaultThresholds = Thresholds{Excellent: 200, Good: 500, Fair: 1000}
+
+// WithThresholds 设置全局延迟评级阈值，未设置的字段使用默认值（站点的 Thresholds 配置优先）
+func WithThresholds(th Thresholds) Option {
+	return func(t *Tester) {
+		t.thresholds = DefaultThresholds.Merge(th)
+	}
+}
+
+// Merge 用 o 中非 0 的字段覆盖 t，返回合并后的阈值
+func (t Thresholds) Merge(o Thresholds) Thresholds {
+	if o.Excellent > 0 {
+		t.Excellent = o.Excellent
+	}
+	if o.Good > 0 {
+		t.Good = o.Good
+	}
+	if o.Fair > 0 {
+		t.Fair = o.Fair
+	}
+	return t
+}
+
+// Validate 检查合并后的阈值是否递增
+func (t Thresholds) Validate() error {
+	if t.Excellent < 0 || t.Good < 0 || t.Fair < 0 {
+		return fmt.Errorf("评级阈值不能为负数: %+v", t)
+	}
+	if t.Excellent >= t.Good || t.Good >= t.Fair {
+		return fmt.Errorf("评级阈值需满足 Excellent < Good < Fair: %d/%d/%d", t.Excellent, t.Good, t.Fair)
+	}
+	return nil
+}
+
+// Rate 根据延迟评级
+func (t Thresholds) Rate(latency time.Duration) Level {
+	ms := latency.Milliseconds()
+	switch {
+	case ms < int64(t.Excellent):
+		return LevelExcellent
+	case ms < int64(t.Good):
+		return LevelGood
+	case ms < int64(t.Fair):
+		return LevelFair
+	default:
+		return LevelPoor
+	}
+}
+
+// RateMbps 根据吞吐速率评级
+func RateMbps(mbps float64) Level {
+	switch {
+	case mbps >= 100:
+		return LevelExcellent
+	case mbps >= 30:
+		return LevelGood
+	case mbps >= 10:
+		return LevelFair
+	default:
+		return LevelPoor
+	}
+}
+
+// OverallLevel 根据各站点的评级计算整体网络质量（已评级站点的平均等级），没有已评级的站点时返回 LevelNone
+func OverallLevel(results []TestResult) Level {
+	var sum, n int
+	for _, result := range results {
+		if result.Success && result.Level != LevelNone {
+			sum += int(result.Level)
+			n++
+		}
+	}
+	if n == 0 {
+		return LevelNone
+	}
+	return Level(math.Round(float64(sum) / float64(n)))
+}
+
+// thresholdsFor 返回站点生效的评级阈值
+func (t *Tester) thresholdsFor(site Site) Thresholds {
+	if site.Thresholds == nil {
+		return t.thresholds
+	}
+	return t.thresholds.Merge(*site.Thresholds)
+}
+
+// rate 按站点的评级阈值为成功的结果评级，降级的结果保留"降级"状态
+func (t *Tester) rate(result *TestResult, site Site) {
+	if !result.Success {
+		return
+	}
+	result.Level = t.thresholdsFor(site).Rate(result.Latency)
+	if !result.Degraded {
+		result.Status = result.Level.String()
+	}
+}
@@ -0,0 +1,91 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestThresholds_Rate 测试按阈值评级
+func TestThresholds_Rate(t *testing.T) {
+	local := DefaultThresholds.Merge(Thresholds{Excellent: 20, Good: 50, Fair: 100})
+
+	tests := []struct {
+		name    string
+		th      Thresholds
+		latency time.Duration
+		want    Level
+	}{
+		{"默认-优秀", DefaultThresholds, 150 * time.Millisecond, LevelExcellent},
+		{"默认-良好", DefaultThresholds, 200 * time.Millisecond, LevelGood},
+		{"默认-一般", DefaultThresholds, 800 * time.Millisecond, LevelFair},
+		{"默认-较差", DefaultThresholds, 1500 * time.Millisecond, LevelPoor},
+		{"同城-较差", local, 150 * time.Millisecond, LevelPoor},
+		{"同城-优秀", local, 10 * time.Millisecond, LevelExcellent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.th.Rate(tt.latency); got != tt.want {
+				t.Errorf("Rate(%v) = %s, want %s", tt.latency, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestThresholds_Validate 测试阈值校验
+func TestThresholds_Validate(t *testing.T) {
+	if err := DefaultThresholds.Validate(); err != nil {
+		t.Errorf("默认阈值应有效: %v", err)
+	}
+	if err := DefaultThresholds.Merge(Thresholds{Excellent: 600}).Validate(); err == nil {
+		t.Errorf("Excellent 大于 Good 时应报错")
+	}
+}
+
+// TestOverallLevel 测试整体评级由各站点的评级决定
+func TestOverallLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []TestResult
+		want    Level
+	}{
+		{"无结果", nil, LevelNone},
+		{"全部失败", []TestResult{{Success: false}}, LevelNone},
+		{"全部优秀", []TestResult{{Success: true, Level: LevelExcellent}, {Success: true, Level: LevelExcellent}}, LevelExcellent},
+		{"平均", []TestResult{{Success: true, Level: LevelExcellent}, {Success: true, Level: LevelFair}}, LevelGood},
+		{"忽略失败", []TestResult{{Success: true, Level: LevelPoor}, {Success: false, Level: LevelNone}}, LevelPoor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OverallLevel(tt.results); got != tt.want {
+				t.Errorf("OverallLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_Thresholds 测试全局与站点的评级阈值
+func TestTester_TestSite_Thresholds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second, WithThresholds(Thresholds{Excellent: 1000, Good: 2000, Fair: 3000}))
+
+	result := tr.TestSite(context.Background(), Site{Name: "global", URL: server.URL})
+	if result.Level != LevelExcellent || result.Status != "优秀" {
+		t.Errorf("全局阈值: Level = %s, Status = %s, want 优秀", result.Level, result.Status)
+	}
+
+	strict := &Thresholds{Excellent: 1, Good: 2, Fair: 5}
+	result = tr.TestSite(context.Background(), Site{Name: "site", URL: server.URL, Thresholds: strict})
+	if result.Level != LevelPoor || result.Status != "较差" {
+		t.Errorf("站点阈值: Level = %s, Status = %s, want 较差", result.Level, result.Status)
+	}
+}
@@ -0,0 +1,143 @@
+package tester
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// DefaultMaxRedirects 默认最多跟随的重定向次数（与 http.Client 默认策略一致）
+const DefaultMaxRedirects = 10
+
+// 重定向策略
+const (
+	RedirectFollow = "follow" // 跟随重定向，最多 DefaultMaxRedirects 次
+	RedirectNone   = "none"   // 不跟随，以 3xx 响应作为结果
+)
+
+// RedirectHop 重定向链中的一次请求
+type RedirectHop struct {
+	URL        string
+	StatusCode int
+	Latency    time.Duration
+	Unexpected bool // 跳转到了非预期的域名（如运营商劫持页）
+}
+
+// ParseRedirectPolicy 解析重定向策略，返回最多跟随的次数
+// 支持 "follow"（默认）、"none" 和表示最大跳数的数字
+func ParseRedirectPolicy(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", RedirectFollow:
+		return DefaultMaxRedirects, nil
+	case RedirectNone:
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("无效的重定向策略: %s (支持 follow, none 或最大跳数)", s)
+	}
+	return n, nil
+}
+
+// WithMaxRedirects 设置默认最多跟随的重定向次数，0 表示不跟随（站点的 Redirect 配置优先）
+func WithMaxRedirects(n int) Option {
+	return func(t *Tester) {
+		t.maxRedirects = n
+	}
+}
+
+// redirectLimit 返回站点最多跟随的重定向次数，站点未配置时使用 fallback
+func (s Site) redirectLimit(fallback int) (int, error) {
+	if s.Redirect == "" {
+		return fallback, nil
+	}
+	return ParseRedirectPolicy(s.Redirect)
+}
+
+// noRedirectClient 返回不自动跟随重定向的客户端副本，由探测逐跳跟随并记录
+func noRedirectClient(client *http.Client) *http.Client {
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &c
+}
+
+// redirectTarget 返回重定向响应指向的地址，不是重定向时返回 nil
+func redirectTarget(resp *http.Response) *url.URL {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil
+	}
+
+	loc, err := resp.Location()
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// redirectMethod 按状态码决定重定向后的请求方法，以及是否保留请求体（与 http.Client 行为一致）
+func redirectMethod(status int, method string) (string, bool) {
+	switch status {
+	case http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return method, true
+	default:
+		if method != http.MethodGet && method != http.MethodHead {
+			return http.MethodGet, false
+		}
+		return method, false
+	}
+}
+
+// expectedRedirect 判断重定向目标是否属于站点自身的域名或 RedirectHosts 中允许的域名
+func (s Site) expectedRedirect(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+
+	origin, err := url.Parse(s.URL)
+	if err == nil && sameSite(strings.ToLower(origin.Hostname()), host) {
+		return true
+	}
+
+	for _, allowed := range s.RedirectHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSite 两个主机名是否属于同一个可注册域名（如 www.example.com 与 example.com）
+func sameSite(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if net.ParseIP(a) != nil || net.ParseIP(b) != nil {
+		return false
+	}
+
+	da, errA := publicsuffix.EffectiveTLDPlusOne(a)
+	db, errB := publicsuffix.EffectiveTLDPlusOne(b)
+	return errA == nil && errB == nil && da == db
+}
+
+// add 累加两次请求的各阶段耗时
+func (t Timing) add(o Timing) Timing {
+	return Timing{
+		DNS:     t.DNS + o.DNS,
+		Connect: t.Connect + o.Connect,
+		TLS:     t.TLS + o.TLS,
+		TTFB:    t.TTFB + o.TTFB,
+		Total:   t.Total + o.Total,
+	}
+}
@@ -0,0 +1,129 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseRedirectPolicy 测试重定向策略解析
+func TestParseRedirectPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"", DefaultMaxRedirects, false},
+		{"follow", DefaultMaxRedirects, false},
+		{"none", 0, false},
+		{"3", 3, false},
+		{"-1", 0, true},
+		{"always", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRedirectPolicy(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseRedirectPolicy(%q) = (%d, %v), want (%d, wantErr %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestSite_ExpectedRedirect 测试跨域跳转的判断
+func TestSite_ExpectedRedirect(t *testing.T) {
+	site := Site{URL: "http://example.com", RedirectHosts: []string{"example-cdn.net"}}
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"https://example.com/", true},
+		{"https://www.example.com/", true},
+		{"https://static.example-cdn.net/", true},
+		{"http://hijack.isp.net/", false},
+		{"http://10.0.0.1/", false},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.target)
+		if got := site.expectedRedirect(u); got != tt.want {
+			t.Errorf("expectedRedirect(%s) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestTester_TestSite_Redirects 测试重定向链的记录与策略
+func TestTester_TestSite_Redirects(t *testing.T) {
+	// 另一个主机名下的服务，模拟劫持页
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case "/c":
+			w.WriteHeader(http.StatusOK)
+		case "/hijack":
+			http.Redirect(w, r, otherURL+"/landing", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		site         Site
+		opts         []Option
+		wantStatus   int
+		wantHops     int
+		wantDegraded bool
+	}{
+		{"跟随", Site{Path: "/a"}, nil, http.StatusOK, 3, false},
+		{"不跟随", Site{Path: "/a"}, []Option{WithMaxRedirects(0)}, http.StatusMovedPermanently, 0, false},
+		{"站点策略优先", Site{Path: "/a", Redirect: "none"}, []Option{WithMaxRedirects(5)}, http.StatusMovedPermanently, 0, false},
+		{"超过最大跳数", Site{Path: "/a", Redirect: "1"}, nil, http.StatusFound, 2, true},
+		{"非预期域名", Site{Path: "/hijack", Headers: map[string]string{"Authorization": "Bearer secret"}, ExpectStatus: []string{"2xx"}}, nil, http.StatusOK, 2, true},
+		{"允许的域名", Site{Path: "/hijack", RedirectHosts: []string{"localhost"}}, nil, http.StatusOK, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.site.Name = tt.name
+			tt.site.URL = server.URL
+
+			tr := NewTester(server.Client(), 5*time.Second, tt.opts...)
+			result := tr.TestSite(context.Background(), tt.site)
+
+			if !result.Success {
+				t.Fatalf("TestSite() failed: %s", result.Error)
+			}
+			if result.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", result.StatusCode, tt.wantStatus)
+			}
+			if len(result.Redirects) != tt.wantHops {
+				t.Errorf("len(Redirects) = %d, want %d", len(result.Redirects), tt.wantHops)
+			}
+			if result.Degraded != tt.wantDegraded {
+				t.Errorf("Degraded = %v (%v), want %v", result.Degraded, result.Warnings, tt.wantDegraded)
+			}
+			for _, hop := range result.Redirects {
+				if hop.Latency <= 0 || hop.StatusCode == 0 {
+					t.Errorf("hop %+v missing status or latency", hop)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package tester
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 认证方式
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+// Auth 请求认证配置，密码和令牌从环境变量读取，避免明文写入配置文件
+type Auth struct {
+	Type        string `json:"Type"`                  // basic / bearer
+	Username    string `json:"Username,omitempty"`    // basic 认证用户名
+	PasswordEnv string `json:"PasswordEnv,omitempty"` // basic 认证密码所在的环境变量
+	TokenEnv    string `json:"TokenEnv,omitempty"`    // bearer 令牌所在的环境变量
+}
+
+// hasCustomRequest 站点是否自定义了请求
+func (s Site) hasCustomRequest() bool {
+	return s.Method != "" || s.Path != "" || len(s.Headers) > 0 ||
+		s.Body != "" || s.BodyFile != "" || s.Auth != nil
+}
+
+// hasBody 站点是否配置了请求体
+func (s Site) hasBody() bool {
+	return s.Body != "" || s.BodyFile != ""
+}
+
+// requestPlan 决定探测使用的请求方法和地址，fallback 表示失败后是否降级为 GET 首页
+func (s Site) requestPlan() (method, target string, fallback bool) {
+	switch {
+	case s.hasCustomRequest():
+		// 自定义请求：默认 GET，带请求体时默认 POST
+		method = strings.ToUpper(s.Method)
+		if method == "" {
+			method = "GET"
+			if s.hasBody() {
+				method = "POST"
+			}
+		}
+		return method, s.URL + s.Path, false
+	case s.hasAssertions():
+		// 配置了成功条件时直接 GET 站点，部分服务器对 HEAD 返回 405/501，会导致状态码校验误报
+		return "GET", s.URL, false
+	default:
+		return "HEAD", s.URL + "/favicon.ico", true
+	}
+}
+
+// newRequest 按站点配置构造请求：请求体、请求头和认证
+func newRequest(ctx context.Context, site Site, method, target string) (*http.Request, error) {
+	return buildRequest(ctx, site, method, target, true, true)
+}
+
+// buildRequest 构造请求，withBody 和 withCredentials 控制是否附带请求体、站点请求头与认证
+// 重定向到其他主机时不附带请求头和认证，避免泄露凭据
+func buildRequest(ctx context.Context, site Site, method, target string, withBody, withCredentials bool) (*http.Request, error) {
+	var reader io.Reader
+	if withBody {
+		body, err := site.requestBody()
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if !withCredentials {
+		return req, nil
+	}
+
+	for name, value := range site.Headers {
+		value = expandEnvRefs(value)
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+
+	if site.Auth != nil {
+		if err := site.Auth.apply(req); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// envRef 请求头中的环境变量引用，只识别 ${VAR} 形式
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// HasEnvRef 请求头的值中是否引用了环境变量（${VAR}）
+func HasEnvRef(value string) bool {
+	return envRef.MatchString(value)
+}
+
+// expandEnvRefs 将 ${VAR} 替换为环境变量的值，其他的 $（如令牌、签名中的字符）原样保留
+func expandEnvRefs(value string) string {
+	return envRef.ReplaceAllStringFunc(value, func(ref string) string {
+		return os.Getenv(ref[2 : len(ref)-1])
+	})
+}
+
+// requestBody 返回请求体，BodyFile 优先于 Body
+func (s Site) requestBody() ([]byte, error) {
+	if s.BodyFile != "" {
+		data, err := os.ReadFile(s.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取请求体文件失败: %v", err)
+		}
+		return data, nil
+	}
+	if s.Body != "" {
+		return []byte(s.Body), nil
+	}
+	return nil, nil
+}
+
+// apply 为请求设置认证信息
+func (a *Auth) apply(req *http.Request) error {
+	switch strings.ToLower(a.Type) {
+	case AuthBasic:
+		password, err := lookupSecret(a.PasswordEnv)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(a.Username, password)
+	case AuthBearer:
+		token, err := lookupSecret(a.TokenEnv)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return fmt.Errorf("不支持的认证方式: %s", a.Type)
+	}
+	return nil
+}
+
+// lookupSecret 从环境变量读取密钥
+func lookupSecret(env string) (string, error) {
+	if env == "" {
+		return "", fmt.Errorf("认证配置缺少环境变量名")
+	}
+	value, ok := os.LookupEnv(env)
+	if !ok || value == "" {
+		return "", fmt.Errorf("环境变量 %s 未设置", env)
+	}
+	return value, nil
+}
@@ -0,0 +1,135 @@
+package tester
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSite_RequestPlan 测试请求方法和地址的选择
+func TestSite_RequestPlan(t *testing.T) {
+	tests := []struct {
+		name         string
+		site         Site
+		wantMethod   string
+		wantTarget   string
+		wantFallback bool
+	}{
+		{"默认 favicon", Site{URL: "https://a.com"}, "HEAD", "https://a.com/favicon.ico", true},
+		{"成功条件", Site{URL: "https://a.com", ExpectStatus: []string{"2xx"}}, "GET", "https://a.com", false},
+		{"校验响应体", Site{URL: "https://a.com", BodyContains: "ok"}, "GET", "https://a.com", false},
+		{"自定义路径", Site{URL: "https://a.com", Path: "/healthz"}, "GET", "https://a.com/healthz", false},
+		{"带请求体", Site{URL: "https://a.com", Body: "{}"}, "POST", "https://a.com", false},
+		{"指定方法", Site{URL: "https://a.com", Method: "put", Body: "{}"}, "PUT", "https://a.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, target, fallback := tt.site.requestPlan()
+			if method != tt.wantMethod || target != tt.wantTarget || fallback != tt.wantFallback {
+				t.Errorf("requestPlan() = (%s, %s, %v), want (%s, %s, %v)",
+					method, target, fallback, tt.wantMethod, tt.wantTarget, tt.wantFallback)
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_CustomRequest 测试自定义请求方法、请求头、请求体和认证
+func TestTester_TestSite_CustomRequest(t *testing.T) {
+	t.Setenv("NETSPEED_TEST_TOKEN", "secret-token")
+	t.Setenv("NETSPEED_TEST_PASSWORD", "secret-password")
+	t.Setenv("NETSPEED_TEST_TENANT", "acme")
+
+	bodyFile := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(bodyFile, []byte(`{"check":"deep"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/bearer":
+			if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret-token" ||
+				r.Host != "api.internal" || r.Header.Get("X-Tenant") != "acme" || string(body) != `{"check":"deep"}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case "/literal":
+			if r.Header.Get("X-Signature") != "pa$$w0rd$1 $HOME acme" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case "/basic":
+			user, pass, ok := r.BasicAuth()
+			if r.Method != http.MethodGet || !ok || user != "admin" || pass != "secret-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		site   Site
+		wantOK bool
+	}{
+		{
+			name: "bearer",
+			site: Site{
+				URL:      server.URL,
+				Path:     "/bearer",
+				Headers:  map[string]string{"Host": "api.internal", "X-Tenant": "${NETSPEED_TEST_TENANT}"},
+				BodyFile: bodyFile,
+				Auth:     &Auth{Type: AuthBearer, TokenEnv: "NETSPEED_TEST_TOKEN"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "请求头中的 $ 原样保留",
+			site: Site{
+				URL:     server.URL,
+				Path:    "/literal",
+				Headers: map[string]string{"X-Signature": "pa$$w0rd$1 $HOME ${NETSPEED_TEST_TENANT}"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "basic",
+			site: Site{
+				URL:  server.URL,
+				Path: "/basic",
+				Auth: &Auth{Type: AuthBasic, Username: "admin", PasswordEnv: "NETSPEED_TEST_PASSWORD"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "环境变量未设置",
+			site: Site{
+				URL:  server.URL,
+				Path: "/bearer",
+				Auth: &Auth{Type: AuthBearer, TokenEnv: "NETSPEED_TEST_MISSING"},
+			},
+			wantOK: false,
+		},
+	}
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.site.Name = "local"
+			tt.site.ExpectStatus = []string{"200"}
+			result := tr.TestSite(context.Background(), tt.site)
+
+			if result.Success != tt.wantOK {
+				t.Fatalf("Success = %v, want %v (status=%s, error=%s)", result.Success, tt.wantOK, result.Status, result.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tester
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy 探测失败后的重试策略
+type RetryPolicy struct {
+	Retries   int           // 失败后最多重试的次数（站点的 Retries 配置优先）
+	BaseDelay time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxDelay  time.Duration // 单次等待时间上限
+}
+
+// DefaultRetryPolicy 默认重试策略：不重试，启用重试时从 200ms 开始指数退避
+var DefaultRetryPolicy = RetryPolicy{
+	Retries:   0,
+	BaseDelay: 200 * time.Millisecond,
+	MaxDelay:  5 * time.Second,
+}
+
+// WithRetryPolicy 设置全局重试策略，未设置的等待时间使用默认值
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(t *Tester) {
+		if p.BaseDelay <= 0 {
+			p.BaseDelay = DefaultRetryPolicy.BaseDelay
+		}
+		if p.MaxDelay <= 0 {
+			p.MaxDelay = DefaultRetryPolicy.MaxDelay
+		}
+		t.retry = p
+	}
+}
+
+// backoff 返回第 attempt 次失败后的等待时间：指数退避并加入随机抖动，避免多个站点同时重试
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// 在 [d/2, d] 之间随机取值
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// retryLimit 返回站点失败后最多重试的次数，站点未配置时使用 fallback
+func (s Site) retryLimit(fallback int) int {
+	if s.Retries != nil {
+		return *s.Retries
+	}
+	return fallback
+}
+
+// probeTimeout 返回站点单次探测的超时时间，站点未配置时使用 fallback
+func (s Site) probeTimeout(fallback time.Duration) time.Duration {
+	if s.Timeout > 0 {
+		return time.Duration(s.Timeout * float64(time.Second))
+	}
+	return fallback
+}
+
+// retryable 失败是否可能是暂时的，值得重试
+// 配置错误和证书问题重试也不会改变结果；被取消的结果没有分类，同样不重试
+func retryable(result TestResult) bool {
+	if result.Success || result.Status == "错误" {
+		return false
+	}
+	return result.Category != ErrorNone && result.Category != ErrorTLS
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicy_Backoff 测试指数退避与抖动的范围
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := p.backoff(tt.attempt)
+			if got < tt.max/2 || got > tt.max {
+				t.Fatalf("backoff(%d) = %v, want in [%v, %v]", tt.attempt, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+// TestTester_TestSite_Retries 测试失败后重试并记录探测次数
+func TestTester_TestSite_Retries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 前两次请求直接断开连接
+		if requests.Add(1) <= 2 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	intPtr := func(n int) *int { return &n }
+	policy := WithRetryPolicy(RetryPolicy{Retries: 3, BaseDelay: time.Millisecond})
+
+	tests := []struct {
+		name         string
+		site         Site
+		opts         []Option
+		wantSuccess  bool
+		wantAttempts int
+	}{
+		{"全局重试", Site{}, []Option{policy}, true, 3},
+		{"站点重试优先", Site{Retries: intPtr(1)}, []Option{policy}, false, 2},
+		{"不重试", Site{}, nil, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			tt.site.Name = tt.name
+			tt.site.URL = server.URL
+			tt.site.Path = "/"
+
+			// 每个用例使用新的连接池，避免 Transport 在复用连接上自动重发请求
+			client := &http.Client{Transport: &http.Transport{}}
+			tr := NewTester(client, 5*time.Second, tt.opts...)
+			result := tr.TestSite(context.Background(), tt.site)
+
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v (%s), want %v", result.Success, result.Error, tt.wantSuccess)
+			}
+			if result.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", result.Attempts, tt.wantAttempts)
+			}
+			if result.Retries() != tt.wantAttempts-1 {
+				t.Errorf("Retries() = %d, want %d", result.Retries(), tt.wantAttempts-1)
+			}
+		})
+	}
+}
+
+// TestTester_TestSite_SiteTimeout 测试站点单独设置的超时时间
+func TestTester_TestSite_SiteTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+	tr := NewTester(client, 50*time.Millisecond)
+
+	if result := tr.TestSite(context.Background(), Site{Name: "global", URL: server.URL, Path: "/"}); result.Success {
+		t.Errorf("全局超时 50ms 时应失败")
+	} else if result.Category != ErrorTimeout {
+		t.Errorf("Category = %s, want %s", result.Category, ErrorTimeout)
+	}
+
+	result := tr.TestSite(context.Background(), Site{Name: "slow", URL: server.URL, Path: "/", Timeout: 2})
+	if !result.Success {
+		t.Errorf("站点超时 2s 时应成功: %s", result.Error)
+	}
+}
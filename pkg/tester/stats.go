@@ -0,0 +1,81 @@
+package tester
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LatencyStats 多次采样的延迟统计
+type LatencyStats struct {
+	Samples      int           // 采样次数
+	Min          time.Duration // 最低延迟
+	Median       time.Duration // 中位数
+	P95          time.Duration // 95 分位
+	Max          time.Duration // 最高延迟
+	Jitter       time.Duration // 抖动（标准差）
+	FailureRatio float64       // 失败比例 0-1
+}
+
+// computeStats 根据成功样本的延迟和总采样次数计算统计信息
+func computeStats(latencies []time.Duration, samples int) LatencyStats {
+	stats := LatencyStats{Samples: samples}
+	if samples > 0 {
+		stats.FailureRatio = float64(samples-len(latencies)) / float64(samples)
+	}
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Median = median(sorted)
+	stats.P95 = percentile(sorted, 95)
+	stats.Jitter = stdDev(sorted)
+
+	return stats
+}
+
+// median 计算已排序样本的中位数
+func median(sorted []time.Duration) time.Duration {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// percentile 使用最近秩法计算已排序样本的 p 分位数
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// stdDev 计算样本的总体标准差
+func stdDev(samples []time.Duration) time.Duration {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s)
+	}
+	mean := sum / float64(len(samples))
+
+	var variance float64
+	for _, s := range samples {
+		diff := float64(s) - mean
+		variance += diff * diff
+	}
+	variance /= float64(len(samples))
+
+	return time.Duration(math.Sqrt(variance))
+}
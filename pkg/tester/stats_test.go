@@ -0,0 +1,87 @@
+package tester
+
+import (
+	"testing"
+	"time"
+)
+
+// TestComputeStats 测试多次采样统计
+func TestComputeStats(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		samples   int
+		want      LatencyStats
+	}{
+		{
+			name:      "奇数样本",
+			latencies: []time.Duration{30 * ms, 10 * ms, 20 * ms},
+			samples:   3,
+			want: LatencyStats{
+				Samples: 3,
+				Min:     10 * ms,
+				Median:  20 * ms,
+				P95:     30 * ms,
+				Max:     30 * ms,
+				Jitter:  8164965, // sqrt(200/3) ms
+			},
+		},
+		{
+			name:      "偶数样本与失败",
+			latencies: []time.Duration{10 * ms, 40 * ms, 20 * ms, 30 * ms},
+			samples:   5,
+			want: LatencyStats{
+				Samples:      5,
+				Min:          10 * ms,
+				Median:       25 * ms,
+				P95:          40 * ms,
+				Max:          40 * ms,
+				Jitter:       11180339, // sqrt(125) ms
+				FailureRatio: 0.2,
+			},
+		},
+		{
+			name:    "全部失败",
+			samples: 3,
+			want: LatencyStats{
+				Samples:      3,
+				FailureRatio: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeStats(tt.latencies, tt.samples)
+			if got != tt.want {
+				t.Errorf("computeStats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPercentile 测试分位数计算
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 20)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{p: 50, want: 10 * time.Millisecond},
+		{p: 95, want: 19 * time.Millisecond},
+		{p: 100, want: 20 * time.Millisecond},
+		{p: 0, want: 1 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package tester
+
+import "strings"
+
+// 分组方式
+const (
+	GroupByGroup = "group" // 按站点的 Group 分组
+	GroupByTag   = "tag"   // 按站点的 Tags 分组，带多个标签的站点计入每个分组
+)
+
+// Ungrouped 没有分组或标签的站点所在的分组名
+const Ungrouped = "未分组"
+
+// HasAnyTag 站点的 Tags 或 Group 是否包含 tags 中的任意一个（不区分大小写）
+func (s Site) HasAnyTag(tags []string) bool {
+	for _, want := range tags {
+		if strings.EqualFold(s.Group, want) {
+			return true
+		}
+		for _, tag := range s.Tags {
+			if strings.EqualFold(tag, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GroupKeys 返回站点按指定方式所属的分组
+func (s Site) GroupKeys(by string) []string {
+	switch by {
+	case GroupByTag:
+		if len(s.Tags) > 0 {
+			return s.Tags
+		}
+	case GroupByGroup:
+		if s.Group != "" {
+			return []string{s.Group}
+		}
+	}
+	return []string{Ungrouped}
+}
@@ -11,105 +11,258 @@ import (
 type Tester struct {
 	client  *http.Client
 	timeout time.Duration
+	samples int
+
+	concurrency int          // 最大并发数，0 表示不限
+	limiter     *rateLimiter // 请求限速，nil 表示不限
+	perHost     bool         // 同一主机的站点是否串行测试
+	hosts       hostLocks
+
+	certWarnDays int      // 证书剩余天数低于该值时标记为降级，0 表示不检查
+	connMode     ConnMode // HTTP 探测的连接复用方式
+	maxRedirects int      // 默认最多跟随的重定向次数
+	retry        RetryPolicy
+	thresholds   Thresholds // 全局延迟评级阈值
+
+	family Family                      // 探测使用的 IP 地址族
+	dial   DialContextFunc             // TCP/TLS/DNS 探测的拨号方式，nil 表示直连
+	custom map[string]Probe            // 通过 WithProbe 注册的探测实现
+	probes map[Family]map[string]Probe // 按地址族和 URL 协议注册的内置探测实现
+}
+
+// Option 测试器配置项
+type Option func(*Tester)
+
+// WithSamples 设置每个站点的采样次数（小于 1 时按 1 处理）
+func WithSamples(n int) Option {
+	return func(t *Tester) {
+		if n < 1 {
+			n = 1
+		}
+		t.samples = n
+	}
+}
+
+// WithConcurrency 设置 TestAll 同时测试的最大站点数（小于 1 表示不限）
+func WithConcurrency(n int) Option {
+	return func(t *Tester) {
+		t.concurrency = n
+	}
+}
+
+// WithRateLimit 设置每秒最多建立的探测连接数（小于等于 0 表示不限），双栈和冷/热对比时分别计数
+func WithRateLimit(rps float64) Option {
+	return func(t *Tester) {
+		t.limiter = newRateLimiter(rps)
+	}
+}
+
+// WithPerHostSerial 设置同一主机的站点是否串行测试，避免互相干扰
+func WithPerHostSerial(enabled bool) Option {
+	return func(t *Tester) {
+		t.perHost = enabled
+	}
+}
+
+// WithCertWarnDays 设置证书过期告警阈值（天），剩余天数低于该值的站点标记为降级，0 表示不检查
+func WithCertWarnDays(days int) Option {
+	return func(t *Tester) {
+		t.certWarnDays = days
+	}
 }
 
 // NewTester 创建新的测试器
-func NewTester(client *http.Client, timeout time.Duration) *Tester {
-	return &Tester{
-		client:  client,
-		timeout: timeout,
+func NewTester(client *http.Client, timeout time.Duration, opts ...Option) *Tester {
+	t := &Tester{
+		client:   client,
+		timeout:  timeout,
+		samples:  1,
+		connMode: ConnPooled,
+
+		maxRedirects: DefaultMaxRedirects,
+		retry:        DefaultRetryPolicy,
+		thresholds:   DefaultThresholds,
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	t.registerDefaultProbes()
+	return t
 }
 
-// TestAll 并行测试所有网站
-func (t *Tester) TestAll(sites []Site) []TestResult {
-	var wg sync.WaitGroup
+// TestAll 并行测试所有网站，结果顺序与 sites 一致
+// ctx 取消后进行中的探测会被中断，尚未开始的站点标记为已取消
+func (t *Tester) TestAll(ctx context.Context, sites []Site) []TestResult {
 	results := make([]TestResult, len(sites))
+	t.TestStream(ctx, sites, func(idx int, result TestResult) {
+		results[idx] = result
+	})
+	return results
+}
+
+// TestStream 并行测试所有网站，每个站点完成时立即以其在 sites 中的下标回调 fn
+// fn 按完成顺序串行调用，无需自行加锁；所有站点回调完成后返回
+// ctx 取消后进行中的探测会被中断，尚未开始的站点以已取消的结果回调
+func (t *Tester) TestStream(ctx context.Context, sites []Site, fn func(idx int, result TestResult)) {
+	var mu sync.Mutex
+
+	workers := t.concurrency
+	if workers < 1 || workers > len(sites) {
+		workers = len(sites)
+	}
 
-	for i, site := range sites {
+	// 工作池：每个 worker 从 jobs 中取站点索引，测试完成后回调
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(idx int, s Site) {
+		go func() {
 			defer wg.Done()
-			results[idx] = t.TestSite(s)
-		}(i, site)
+			for idx := range jobs {
+				result := t.testSiteInPool(ctx, sites[idx])
+				mu.Lock()
+				fn(idx, result)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range sites {
+		jobs <- i
 	}
+	close(jobs)
 
 	wg.Wait()
-	return results
 }
 
-// TestSite 测试单个网站的延迟
-func (t *Tester) TestSite(site Site) TestResult {
-	result := TestResult{
-		Name:    site.Name,
-		URL:     site.URL,
-		Success: false,
+// testSiteInPool 在工作池中测试站点，按需对同一主机串行化
+func (t *Tester) testSiteInPool(ctx context.Context, site Site) TestResult {
+	if ctx.Err() != nil {
+		return canceledResult(site)
 	}
+	if t.perHost {
+		unlock := t.hosts.lock(site)
+		defer unlock()
+	}
+	return t.TestSite(ctx, site)
+}
 
-	// 创建带超时的请求
-	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
-	defer cancel()
+// TestSite 测试单个网站的延迟
+// 采样次数大于 1 时多次探测，以中位数作为延迟并据此评级
+func (t *Tester) TestSite(ctx context.Context, site Site) TestResult {
+	if t.samples <= 1 {
+		return t.probeOnce(ctx, site)
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "HEAD", site.URL+"/favicon.ico", nil)
-	if err != nil {
-		result.Error = err.Error()
-		result.Status = "错误"
-		return result
+	var succeeded []TestResult
+	var latencies []time.Duration
+	var last TestResult
+	attempts := 0
+	for i := 0; i < t.samples && ctx.Err() == nil; i++ {
+		last = t.probeOnce(ctx, site)
+		attempts += last.Attempts
+		if last.Success {
+			succeeded = append(succeeded, last)
+			latencies = append(latencies, last.Latency)
+		}
 	}
 
-	// 记录开始时间
-	start := time.Now()
+	if ctx.Err() != nil {
+		return canceledResult(site)
+	}
 
-	// 发送请求
-	resp, err := t.client.Do(req)
-	latency := time.Since(start)
+	stats := computeStats(latencies, t.samples)
+	if len(succeeded) == 0 {
+		// 全部失败，沿用最后一次的错误信息
+		last.Stats = stats
+		last.Attempts = attempts
+		return last
+	}
 
-	if err != nil {
-		// 降级：尝试 GET 首页
-		result = t.fallbackTest(site, ctx)
-		if !result.Success {
-			result.Status = "超时"
+	// 以最接近中位数的样本作为阶段耗时的代表
+	result := succeeded[0]
+	for _, r := range succeeded[1:] {
+		if absDuration(r.Latency-stats.Median) < absDuration(result.Latency-stats.Median) {
+			result = r
 		}
-		return result
 	}
-	defer resp.Body.Close()
-
-	result.Latency = latency
-	result.Success = true
-	result.Status = GetStatusByLatency(latency)
+	result.Latency = stats.Median
+	result.Stats = stats
+	result.Attempts = attempts
+	t.rate(&result, site)
 
 	return result
 }
 
-// fallbackTest 降级测试（使用 GET 请求）
-func (t *Tester) fallbackTest(site Site, ctx context.Context) TestResult {
-	result := TestResult{
-		Name:    site.Name,
-		URL:     site.URL,
-		Success: false,
+// probeOnce 对网站进行一次探测，失败时按重试策略退避后重试
+func (t *Tester) probeOnce(parent context.Context, site Site) TestResult {
+	retries := site.retryLimit(t.retry.Retries)
+	for attempt := 1; ; attempt++ {
+		var result TestResult
+		if t.family == FamilyBoth {
+			result = t.probeDualStack(parent, site)
+		} else {
+			result = t.probeFamily(parent, site, t.family)
+		}
+		result.Attempts = attempt
+
+		if attempt > retries || !retryable(result) {
+			return result
+		}
+		if err := sleepContext(parent, t.retry.backoff(attempt)); err != nil {
+			return canceledResult(site)
+		}
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", site.URL, nil)
+// probeFamily 通过指定地址族探测一次
+func (t *Tester) probeFamily(parent context.Context, site Site, family Family) TestResult {
+	probe, err := t.probeFor(site, family)
 	if err != nil {
-		result.Error = err.Error()
-		result.Status = "错误"
-		return result
+		return errorResult(site, err)
 	}
 
-	start := time.Now()
-	resp, err := t.client.Do(req)
-	latency := time.Since(start)
-
-	if err != nil {
-		result.Error = err.Error()
-		result.Status = "超时"
-		return result
+	// 限速在探测超时之前等待，按本次探测建立的连接数计入
+	if err := t.limiter.wait(parent, connectionsPerProbe(probe)); err != nil {
+		return canceledResult(site)
 	}
-	defer resp.Body.Close()
 
-	result.Latency = latency
-	result.Success = true
-	result.Status = GetStatusByLatency(latency)
+	// 创建带超时的探测
+	ctx, cancel := context.WithTimeout(parent, site.probeTimeout(t.timeout))
+	defer cancel()
 
+	result := probe.Probe(ctx, site)
+	if parent.Err() != nil {
+		// 被外部取消，而不是超时
+		return canceledResult(site)
+	}
+	t.rate(&result, site)
 	return result
 }
+
+// connectionsPerProbe 一次探测建立的连接数：both 模式的 HTTP 探测分别建立冷连接和热连接
+// 降级为 GET、再次请求以测量复用连接时使用已建立的连接，不另外计数
+func connectionsPerProbe(probe Probe) int {
+	if p, ok := probe.(*httpProbe); ok && p.mode == ConnBoth {
+		return 2
+	}
+	return 1
+}
+
+// canceledResult 返回被取消的测试结果
+func canceledResult(site Site) TestResult {
+	return TestResult{
+		Name:   site.Name,
+		URL:    site.URL,
+		Status: "已取消",
+		Error:  context.Canceled.Error(),
+	}
+}
+
+// absDuration 返回时长的绝对值
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,178 @@
+package tester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTester_TestSite_Timing 测试各阶段耗时记录
+func TestTester_TestSite_Timing(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	result := tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL})
+
+	if !result.Success {
+		t.Fatalf("TestSite() failed: %s", result.Error)
+	}
+
+	timing := result.Timing
+	if timing.Connect <= 0 {
+		t.Errorf("Connect = %v, want > 0", timing.Connect)
+	}
+	if timing.TLS <= 0 {
+		t.Errorf("TLS = %v, want > 0", timing.TLS)
+	}
+	if timing.TTFB < 20*time.Millisecond {
+		t.Errorf("TTFB = %v, want >= 20ms", timing.TTFB)
+	}
+	if timing.Total != result.Latency {
+		t.Errorf("Total = %v, want %v", timing.Total, result.Latency)
+	}
+	if timing.TTFB > timing.Total {
+		t.Errorf("TTFB %v should not exceed Total %v", timing.TTFB, timing.Total)
+	}
+}
+
+// TestTester_TestSite_Fallback 测试 HEAD 失败后降级为 GET
+func TestTester_TestSite_Fallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			// 直接断开连接，模拟 HEAD 请求失败
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			conn, _, _ := hj.Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	result := tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL})
+
+	if !result.Success {
+		t.Fatalf("TestSite() failed: %s", result.Error)
+	}
+	if result.Timing.Total != result.Latency {
+		t.Errorf("Total = %v, want %v", result.Timing.Total, result.Latency)
+	}
+}
+
+// TestTester_TestSite_Samples 测试多次采样
+func TestTester_TestSite_Samples(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), 5*time.Second, WithSamples(5))
+	result := tr.TestSite(context.Background(), Site{Name: "local", URL: server.URL})
+
+	if !result.Success {
+		t.Fatalf("TestSite() failed: %s", result.Error)
+	}
+	if got := atomic.LoadInt32(&requests); got != 5 {
+		t.Errorf("requests = %d, want 5", got)
+	}
+	if result.Stats.Samples != 5 {
+		t.Errorf("Samples = %d, want 5", result.Stats.Samples)
+	}
+	if result.Latency != result.Stats.Median {
+		t.Errorf("Latency = %v, want median %v", result.Latency, result.Stats.Median)
+	}
+	if result.Stats.Min > result.Stats.Median || result.Stats.Median > result.Stats.Max {
+		t.Errorf("unordered stats: %+v", result.Stats)
+	}
+	if result.Stats.FailureRatio != 0 {
+		t.Errorf("FailureRatio = %v, want 0", result.Stats.FailureRatio)
+	}
+}
+
+// TestTester_TestAll_Canceled 测试取消后中断进行中的探测
+func TestTester_TestAll_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	sites := []Site{
+		{Name: "A", URL: server.URL},
+		{Name: "B", URL: server.URL},
+		{Name: "C", URL: server.URL},
+	}
+
+	tr := NewTester(server.Client(), 10*time.Second, WithConcurrency(1))
+	start := time.Now()
+	results := tr.TestAll(ctx, sites)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("elapsed = %v, cancel should abort in-flight probes", elapsed)
+	}
+	for i, result := range results {
+		if result.Success || result.Status != "已取消" {
+			t.Errorf("results[%d] = %+v, want canceled", i, result)
+		}
+		if result.Name != sites[i].Name {
+			t.Errorf("results[%d].Name = %s, want %s", i, result.Name, sites[i].Name)
+		}
+	}
+}
+
+// TestTester_TestStream 测试每个站点完成后立即回调，不等待最慢的站点
+func TestTester_TestStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sites := []Site{
+		{Name: "Slow", URL: server.URL, Path: "/slow"},
+		{Name: "Fast", URL: server.URL, Path: "/fast"},
+	}
+
+	tr := NewTester(server.Client(), 5*time.Second)
+	start := time.Now()
+	var order []int
+	var fastAt time.Duration
+	tr.TestStream(context.Background(), sites, func(idx int, result TestResult) {
+		order = append(order, idx)
+		if result.Name != sites[idx].Name {
+			t.Errorf("callback idx %d got result %s", idx, result.Name)
+		}
+		if idx == 1 {
+			fastAt = time.Since(start)
+		}
+	})
+
+	if len(order) != 2 || order[0] != 1 || order[1] != 0 {
+		t.Fatalf("callback order = %v, want [1 0]", order)
+	}
+	if fastAt >= 500*time.Millisecond {
+		t.Errorf("fast site reported after %v, should not wait for slow site", fastAt)
+	}
+}
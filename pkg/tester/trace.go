@@ -0,0 +1,138 @@
+package tester
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// phaseTracer 基于 httptrace 记录请求各阶段的时间点
+type phaseTracer struct {
+	mu sync.Mutex
+
+	start     time.Time
+	dnsStart  time.Time
+	dnsDone   time.Time
+	connStart time.Time
+	connDone  time.Time
+	tlsStart  time.Time
+	tlsDone   time.Time
+	firstByte time.Time
+
+	connReused bool   // 是否复用了连接池中的连接
+	remoteAddr string // 连接的服务器地址
+}
+
+// withPhaseTrace 为 ctx 挂载 httptrace，返回新的 context 和对应的记录器
+func withPhaseTrace(ctx context.Context) (context.Context, *phaseTracer) {
+	pt := &phaseTracer{}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			pt.mark(&pt.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			pt.mark(&pt.dnsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			pt.mu.Lock()
+			pt.connReused = info.Reused
+			if info.Conn != nil {
+				pt.remoteAddr = hostOf(info.Conn.RemoteAddr())
+			}
+			pt.mu.Unlock()
+		},
+		ConnectStart: func(network, addr string) {
+			// 多地址拨号时只记录第一次
+			pt.markOnce(&pt.connStart)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				pt.mark(&pt.connDone)
+			}
+		},
+		TLSHandshakeStart: func() {
+			pt.mark(&pt.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			pt.mark(&pt.tlsDone)
+		},
+		GotFirstResponseByte: func() {
+			pt.mark(&pt.firstByte)
+		},
+	}
+
+	return httptrace.WithClientTrace(ctx, trace), pt
+}
+
+// begin 记录请求开始时间
+func (pt *phaseTracer) begin() {
+	pt.mark(&pt.start)
+}
+
+// mark 记录当前时间点
+func (pt *phaseTracer) mark(t *time.Time) {
+	pt.mu.Lock()
+	*t = time.Now()
+	pt.mu.Unlock()
+}
+
+// markOnce 仅在时间点尚未记录时写入
+func (pt *phaseTracer) markOnce(t *time.Time) {
+	pt.mu.Lock()
+	if t.IsZero() {
+		*t = time.Now()
+	}
+	pt.mu.Unlock()
+}
+
+// timing 汇总各阶段耗时，total 为整个请求的耗时
+func (pt *phaseTracer) timing(total time.Duration) Timing {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	return Timing{
+		DNS:     span(pt.dnsStart, pt.dnsDone),
+		Connect: span(pt.connStart, pt.connDone),
+		TLS:     span(pt.tlsStart, pt.tlsDone),
+		TTFB:    span(pt.start, pt.firstByte),
+		Total:   total,
+	}
+}
+
+// reused 返回请求是否复用了已有连接
+func (pt *phaseTracer) reused() bool {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.connReused
+}
+
+// remote 返回连接的服务器地址
+func (pt *phaseTracer) remote() string {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.remoteAddr
+}
+
+// hostOf 去掉地址中的端口
+func hostOf(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// span 计算两个时间点之间的间隔，任一未记录时返回 0（如连接复用）
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
@@ -0,0 +1,153 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// 单次上传请求的数据量从 initialUploadChunk 开始，之后按已确认请求的速率估算，
+// 使每个请求约耗时 uploadRequestTime，临近结束时按剩余时间缩小，避免请求在结束时被中断
+const (
+	minUploadChunk     = 64 << 10
+	initialUploadChunk = 256 << 10
+	maxUploadChunk     = 8 << 20
+	uploadRequestTime  = time.Second
+)
+
+// uploadPattern 上传使用的随机数据块，避免被链路压缩
+var uploadPattern = func() []byte {
+	buf := make([]byte, 64*1024)
+	rand.New(rand.NewSource(1)).Read(buf)
+	return buf
+}()
+
+// payloadReader 无限循环输出 uploadPattern
+type payloadReader struct {
+	offset int
+}
+
+func (r *payloadReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		c := copy(p[n:], uploadPattern[r.offset:])
+		n += c
+		r.offset = (r.offset + c) % len(uploadPattern)
+	}
+	return n, nil
+}
+
+// firstByteReader 在开始发送请求体时记录首字节时间
+type firstByteReader struct {
+	r     io.Reader
+	meter *throughputMeter
+	start time.Time
+	sent  bool
+}
+
+func (r *firstByteReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 && !r.sent {
+		r.sent = true
+		r.meter.firstByte(time.Since(r.start))
+	}
+	return n, err
+}
+
+// uploadChunk 根据单个连接的速率（字节/秒）和测试剩余时间决定下一个请求的数据量
+// 剩余时间不足以完成最小请求时返回 0
+func uploadChunk(rate int64, remaining time.Duration) int64 {
+	if rate <= 0 {
+		return initialUploadChunk
+	}
+	target := min(uploadRequestTime, remaining/2)
+	size := int64(float64(rate) * target.Seconds())
+	if size < minUploadChunk {
+		if time.Duration(float64(minUploadChunk)/float64(rate)*float64(time.Second)) > remaining {
+			return 0
+		}
+		size = minUploadChunk
+	}
+	return min(size, maxUploadChunk)
+}
+
+// TestUpload 上传测速：在多个并行连接上持续向 site.UploadURL POST 生成的数据
+// 只有服务器返回 2xx 确认的请求才计入传输量，速率也只根据已确认的请求计算
+func (t *Tester) TestUpload(ctx context.Context, site Site, opts BandwidthOptions) TestResult {
+	result := TestResult{
+		Name:    site.Name,
+		URL:     site.UploadURL,
+		Success: false,
+	}
+
+	if site.UploadURL == "" {
+		result.Error = "未配置 UploadURL"
+		result.Status = "错误"
+		result.Category = ErrorUnknown
+		return result
+	}
+
+	// 上传按请求预先分配传输量，达到上限后连接自然结束，不在请求中途取消
+	limit := opts.Bytes
+	opts.Bytes = 0
+	var reserved atomic.Int64
+	var rate atomic.Int64 // 最近一次确认的请求在单个连接上的速率（字节/秒）
+
+	client := t.streamingClient()
+	tp, ttfb, err := t.runThroughput(ctx, opts, func(ctx context.Context, m *throughputMeter) error {
+		remaining := opts.Duration
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining = time.Until(deadline)
+		}
+		size := uploadChunk(rate.Load(), remaining)
+		if size == 0 {
+			return errStreamDone
+		}
+		if limit > 0 {
+			offset := reserved.Add(size) - size
+			if offset >= limit {
+				return errStreamDone
+			}
+			if remain := limit - offset; remain < size {
+				size = remain
+			}
+		}
+
+		start := time.Now()
+		body := &firstByteReader{
+			r:     io.LimitReader(&payloadReader{}, size),
+			meter: m,
+			start: start,
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", site.UploadURL, body)
+		if err != nil {
+			return err
+		}
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("服务器返回错误: %d", resp.StatusCode)
+		}
+
+		m.ack(start, size)
+		if d := time.Since(start); d > 0 {
+			rate.Store(int64(float64(size) / d.Seconds()))
+		}
+		return nil
+	})
+
+	tp.Direction = DirectionUpload
+	return throughputResult(result, tp, ttfb, err)
+}
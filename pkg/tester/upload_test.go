@@ -0,0 +1,174 @@
+package tester
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newUploadServer 创建接收上传数据的本地服务器
+func newUploadServer(received *int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		n, _ := io.Copy(io.Discard, r.Body)
+		atomic.AddInt64(received, n)
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// TestTester_TestUpload 测试上传测速
+func TestTester_TestUpload(t *testing.T) {
+	t.Run("按时长", func(t *testing.T) {
+		var received int64
+		server := newUploadServer(&received)
+		defer server.Close()
+
+		tr := NewTester(server.Client(), time.Second)
+		opts := BandwidthOptions{Duration: 500 * time.Millisecond, Streams: 2}
+		result := tr.TestUpload(context.Background(), Site{Name: "local", UploadURL: server.URL}, opts)
+
+		if !result.Success {
+			t.Fatalf("TestUpload() failed: %s", result.Error)
+		}
+		tp := result.Throughput
+		if tp.Direction != DirectionUpload {
+			t.Errorf("Direction = %s, want %s", tp.Direction, DirectionUpload)
+		}
+		if tp.Bytes <= 0 || tp.Mbps <= 0 {
+			t.Errorf("Bytes = %d, Mbps = %v, want > 0", tp.Bytes, tp.Mbps)
+		}
+		// 只统计已确认的字节，不会超过服务器实际收到的数据量
+		if got := atomic.LoadInt64(&received); tp.Bytes > got {
+			t.Errorf("Bytes = %d exceeds received %d", tp.Bytes, got)
+		}
+	})
+
+	t.Run("按传输量", func(t *testing.T) {
+		var received int64
+		server := newUploadServer(&received)
+		defer server.Close()
+
+		tr := NewTester(server.Client(), time.Second)
+		opts := BandwidthOptions{Duration: 10 * time.Second, Bytes: 3 << 20, Streams: 2}
+
+		start := time.Now()
+		result := tr.TestUpload(context.Background(), Site{Name: "local", UploadURL: server.URL}, opts)
+
+		if !result.Success {
+			t.Fatalf("TestUpload() failed: %s", result.Error)
+		}
+		if result.Throughput.Bytes != 3<<20 {
+			t.Errorf("Bytes = %d, want %d", result.Throughput.Bytes, 3<<20)
+		}
+		if got := atomic.LoadInt64(&received); got != 3<<20 {
+			t.Errorf("received = %d, want %d", got, 3<<20)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Error("byte limit should stop the test early")
+		}
+	})
+}
+
+// throttle 多个连接共享的限速器
+type throttle struct {
+	mu   sync.Mutex
+	rate float64 // 字节/秒
+	next time.Time
+}
+
+// wait 等待直到允许再接收 n 字节
+func (t *throttle) wait(n int) {
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	t.next = t.next.Add(time.Duration(float64(n) / t.rate * float64(time.Second)))
+	until := t.next
+	t.mu.Unlock()
+	time.Sleep(time.Until(until))
+}
+
+// TestTester_TestUpload_Throttled 测试限速链路上的上传速率接近实际速率
+func TestTester_TestUpload_Throttled(t *testing.T) {
+	const rate = 1e6 // 8 Mbps
+	limiter := &throttle{rate: rate}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 16<<10)
+		for {
+			n, err := r.Body.Read(buf)
+			if n > 0 {
+				limiter.wait(n)
+			}
+			if err != nil {
+				break
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), time.Second)
+	opts := BandwidthOptions{Duration: 3 * time.Second, Streams: 4}
+	result := tr.TestUpload(context.Background(), Site{Name: "local", UploadURL: server.URL}, opts)
+
+	if !result.Success {
+		t.Fatalf("TestUpload() failed: %s", result.Error)
+	}
+	want := rate * 8 / 1e6
+	if got := result.Throughput.Mbps; got < want*0.8 || got > want*1.15 {
+		t.Errorf("Mbps = %.2f, want about %.2f", got, want)
+	}
+}
+
+// TestUploadChunk 测试按速率和剩余时间决定请求数据量
+func TestUploadChunk(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      int64
+		remaining time.Duration
+		want      int64
+	}{
+		{"尚无速率", 0, 10 * time.Second, initialUploadChunk},
+		{"按速率", 1 << 20, 10 * time.Second, 1 << 20},
+		{"临近结束", 1 << 20, time.Second, 512 << 10},
+		{"不超过上限", 1 << 30, 10 * time.Second, maxUploadChunk},
+		{"不低于下限", 16 << 10, 10 * time.Second, minUploadChunk},
+		{"来不及完成", 16 << 10, time.Second, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uploadChunk(tt.rate, tt.remaining); got != tt.want {
+				t.Errorf("uploadChunk(%d, %v) = %d, want %d", tt.rate, tt.remaining, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTester_TestUpload_Rejected 测试服务器拒绝上传
+func TestTester_TestUpload_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	tr := NewTester(server.Client(), time.Second)
+	opts := BandwidthOptions{Duration: 300 * time.Millisecond, Streams: 1}
+	result := tr.TestUpload(context.Background(), Site{Name: "local", UploadURL: server.URL}, opts)
+
+	if result.Success {
+		t.Fatal("TestUpload() should fail when the server rejects the data")
+	}
+	if result.Throughput != nil {
+		t.Error("Throughput should be nil on failure")
+	}
+}